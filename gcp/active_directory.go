@@ -1,6 +1,7 @@
 package gcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -75,18 +76,17 @@ type deleteActiveDirectoryRequest struct {
 	UUID   string `structs:"UUID"`
 }
 
-func (c *Client) createActiveDirectory(request *operateActiveDirectoryRequest) (operateActiveDirectoryResult, error) {
+func (c *Client) createActiveDirectory(ctx context.Context, request *operateActiveDirectoryRequest) (operateActiveDirectoryResult, error) {
 	params := structs.Map(request)
 	baseURL := fmt.Sprintf("%s/Storage/ActiveDirectory", request.Region)
-	statusCode, response, err := c.CallAPIMethod("POST", baseURL, params)
+	_, response, err := c.CallAPIMethod(ctx, "POST", baseURL, params)
 	if err != nil {
 		log.Print("CreateActiveDirectory request failed")
 		return operateActiveDirectoryResult{}, err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "CreateActiveDirectory")
-	if responseError != nil {
-		return operateActiveDirectoryResult{}, responseError
+	if err := c.waitForResponseJobs(ctx, request.Region, response); err != nil {
+		return operateActiveDirectoryResult{}, err
 	}
 
 	var result operateActiveDirectoryResult
@@ -98,7 +98,7 @@ func (c *Client) createActiveDirectory(request *operateActiveDirectoryRequest) (
 	return result, nil
 }
 
-func (c *Client) listActiveDirectoryForRegion(request listActiveDirectoryRequest) (listActiveDirectoryResult, error) {
+func (c *Client) listActiveDirectoryForRegion(ctx context.Context, request listActiveDirectoryRequest) (listActiveDirectoryResult, error) {
 	// GCP only allows one active directory per region.
 
 	//code for terraform import
@@ -113,7 +113,7 @@ func (c *Client) listActiveDirectoryForRegion(request listActiveDirectoryRequest
 	if request.Region == "" {
 		// terraform import: ID = <activeDirectoryID> and no region specified
 		// find all activeDirectories which match activeDirectoryID
-		activeDirectory, err := c.filterAllActiveDirectories(func(v listActiveDirectoryResult) bool {
+		activeDirectory, err := c.filterAllActiveDirectories(ctx, func(v listActiveDirectoryResult) bool {
 			return v.UUID == request.UUID
 		})
 		if err != nil {
@@ -136,17 +136,12 @@ func (c *Client) listActiveDirectoryForRegion(request listActiveDirectoryRequest
 	}
 
 	baseURL := fmt.Sprintf("%s/Storage/ActiveDirectory", request.Region)
-	statusCode, response, err := c.CallAPIMethod("GET", baseURL, nil)
+	_, response, err := c.CallAPIMethod(ctx, "GET", baseURL, nil)
 	if err != nil {
 		log.Print("listActiveDirectory request failed")
 		return listActiveDirectoryResult{}, err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "listActiveDirectoryForRegion")
-	if responseError != nil {
-		return listActiveDirectoryResult{}, responseError
-	}
-
 	var activeDirectories []listActiveDirectoryResult
 	if err := json.Unmarshal(response, &activeDirectories); err != nil {
 		log.Print("Failed to unmarshall response from listActiveDirectoryForRegion")
@@ -167,34 +162,28 @@ func (c *Client) listActiveDirectoryForRegion(request listActiveDirectoryRequest
 	return listActiveDirectoryResult{}, nil
 }
 
-func (c *Client) deleteActiveDirectory(request deleteActiveDirectoryRequest) error {
+func (c *Client) deleteActiveDirectory(ctx context.Context, request deleteActiveDirectoryRequest) error {
 	baseURL := fmt.Sprintf("%s/Storage/ActiveDirectory/%s", request.Region, request.UUID)
-	statusCode, response, err := c.CallAPIMethod("DELETE", baseURL, nil)
+	_, response, err := c.CallAPIMethod(ctx, "DELETE", baseURL, nil)
 	if err != nil {
 		log.Print("deleteActiveDirectory request failed")
 		return err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "deleteActiveDirectory")
-	if responseError != nil {
-		return responseError
-	}
-
-	return nil
+	return c.waitForResponseJobs(ctx, request.Region, response)
 }
 
-func (c *Client) updateActiveDirectory(request operateActiveDirectoryRequest) error {
+func (c *Client) updateActiveDirectory(ctx context.Context, request operateActiveDirectoryRequest) error {
 	params := structs.Map(request)
 	baseURL := fmt.Sprintf("%s/Storage/ActiveDirectory/%s", request.Region, request.UUID)
-	statusCode, response, err := c.CallAPIMethod("PUT", baseURL, params)
+	_, response, err := c.CallAPIMethod(ctx, "PUT", baseURL, params)
 	if err != nil {
 		log.Print("updateActiveDirectory request failed")
 		return err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "updateActiveDirectory")
-	if responseError != nil {
-		return responseError
+	if err := c.waitForResponseJobs(ctx, request.Region, response); err != nil {
+		return err
 	}
 
 	var result listActiveDirectoryResult
@@ -207,22 +196,17 @@ func (c *Client) updateActiveDirectory(request operateActiveDirectoryRequest) er
 }
 
 // Returns volumes of the project. region = "-" for all regions
-func (c *Client) getActiveDirectories(region string) ([]listActiveDirectoryResult, error) {
+func (c *Client) getActiveDirectories(ctx context.Context, region string) ([]listActiveDirectoryResult, error) {
 
 	baseURL := fmt.Sprintf("%s/Storage/ActiveDirectory", region)
 	var result []listActiveDirectoryResult
 
-	statusCode, response, err := c.CallAPIMethod("GET", baseURL, nil)
+	_, response, err := c.CallAPIMethod(ctx, "GET", baseURL, nil)
 	if err != nil {
 		log.Print("getActiveDirectories request failed")
 		return result, err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "getActiveDirectories")
-	if responseError != nil {
-		return result, responseError
-	}
-
 	if err := json.Unmarshal(response, &result); err != nil {
 		log.Print("Failed to unmarshall response from getActiveDirectories")
 		return result, err
@@ -232,10 +216,10 @@ func (c *Client) getActiveDirectories(region string) ([]listActiveDirectoryResul
 
 // Filter all volumes of the project by applying a filter function
 // Example filter function: func(v volumeResult) bool { return v.VolumeID == "1234-5678-90" }
-func (c *Client) filterAllActiveDirectories(f func(listActiveDirectoryResult) bool) ([]listActiveDirectoryResult, error) {
+func (c *Client) filterAllActiveDirectories(ctx context.Context, f func(listActiveDirectoryResult) bool) ([]listActiveDirectoryResult, error) {
 	filteredActiveDirectories := make([]listActiveDirectoryResult, 0)
 
-	vols, err := c.getActiveDirectories("-")
+	vols, err := c.getActiveDirectories(ctx, "-")
 	if err != nil {
 		return filteredActiveDirectories, err
 	}
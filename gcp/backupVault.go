@@ -0,0 +1,90 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/fatih/structs"
+)
+
+// backupVault configures where the backups of the volumes in a region are stored
+type backupVault struct {
+	ID             string `json:"UUID" structs:"UUID,omitempty"`
+	Region         string `json:"region" structs:"region"`
+	BackupRegion   string `json:"backupRegion" structs:"backupRegion"`
+	LifeCycleState string `json:"lifeCycleState" structs:"-"`
+}
+
+func (c *Client) createBackupVault(ctx context.Context, request *backupVault) (backupVault, error) {
+	params := structs.Map(request)
+	baseURL := fmt.Sprintf("%s/Storage/BackupVault", request.Region)
+	log.Printf("params: %#v", redactParams(params))
+	_, response, err := c.CallAPIMethod(ctx, "POST", baseURL, params)
+	if err != nil {
+		log.Print("createBackupVault request failed")
+		return backupVault{}, err
+	}
+
+	if err := c.waitForResponseJobs(ctx, request.Region, response); err != nil {
+		return backupVault{}, err
+	}
+
+	var result backupVault
+	if err := json.Unmarshal(response, &result); err != nil {
+		log.Print("Failed to unmarshall response from createBackupVault")
+		return backupVault{}, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) getBackupVault(ctx context.Context, request *backupVault) (backupVault, error) {
+	baseURL := fmt.Sprintf("%s/Storage/BackupVault/%s", request.Region, request.ID)
+	_, response, err := c.CallAPIMethod(ctx, "GET", baseURL, nil)
+	if err != nil {
+		log.Print("getBackupVault request failed")
+		return backupVault{}, err
+	}
+	var result backupVault
+	if err := json.Unmarshal(response, &result); err != nil {
+		log.Print("Failed to unmarshall response from getBackupVault")
+		return backupVault{}, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) updateBackupVault(ctx context.Context, request *backupVault) (backupVault, error) {
+	params := structs.Map(request)
+	baseURL := fmt.Sprintf("%s/Storage/BackupVault/%s", request.Region, request.ID)
+	_, response, err := c.CallAPIMethod(ctx, "PUT", baseURL, params)
+	if err != nil {
+		log.Print("updateBackupVault request failed")
+		return backupVault{}, err
+	}
+
+	if err := c.waitForResponseJobs(ctx, request.Region, response); err != nil {
+		return backupVault{}, err
+	}
+
+	var result backupVault
+	if err := json.Unmarshal(response, &result); err != nil {
+		log.Print("Failed to unmarshall response from updateBackupVault")
+		return backupVault{}, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) deleteBackupVault(ctx context.Context, request *backupVault) error {
+	baseURL := fmt.Sprintf("%s/Storage/BackupVault/%s", request.Region, request.ID)
+	_, response, err := c.CallAPIMethod(ctx, "DELETE", baseURL, nil)
+	if err != nil {
+		log.Print("deleteBackupVault request failed")
+		return err
+	}
+
+	return c.waitForResponseJobs(ctx, request.Region, response)
+}
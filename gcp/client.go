@@ -1,7 +1,9 @@
 package gcp
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/restapi"
 	"github.com/sirupsen/logrus"
@@ -21,28 +23,35 @@ type Client struct {
 	Project               string
 	Audience              string
 	TokenDuration         int
+	MaxRetries            int
+	MinBackoff            time.Duration
+	MaxBackoff            time.Duration
+	// Region is the provider region, used by resources and data sources which don't set region
+	Region string
+	// DefaultBillingLabels are merged with the billing labels of volumes and storage pools
+	DefaultBillingLabels map[string]string
+	// SkipAuth sends requests without authentication, for a fake API such as cvstest.Server
+	SkipAuth bool
 
 	initOnce      sync.Once
 	restapiClient *restapi.Client
-	requestSlots  chan int
+	requestSlots  requestSlots
 }
 
 // CallAPIMethod can be used to make a request to any GCP API method, receiving results as byte
-func (c *Client) CallAPIMethod(method string, baseURL string, params map[string]interface{}) (int, []byte, error) {
+// The request is aborted when ctx is cancelled or its deadline is exceeded.
+func (c *Client) CallAPIMethod(ctx context.Context, method string, baseURL string, params map[string]interface{}) (int, []byte, error) {
 	c.initOnce.Do(c.init)
 
-	c.waitForAvailableSlot()
-	defer c.releaseSlot()
-
 	ourlog.WithFields(logrus.Fields{
 		"method": method,
-		"params": params,
+		"params": redactParams(params),
 	}).Debug("Calling API")
 
 	if params == nil {
 		params = map[string]interface{}{}
 	}
-	statusCode, result, err := c.restapiClient.Do(baseURL, &restapi.Request{
+	statusCode, result, err := c.restapiClient.Do(ctx, baseURL, &restapi.Request{
 		Method: method,
 		Params: params,
 	})
@@ -59,13 +68,20 @@ func (c *Client) init() {
 	if c.MaxConcurrentRequests == 0 {
 		c.MaxConcurrentRequests = 6
 	}
-	c.requestSlots = make(chan int, c.MaxConcurrentRequests)
+	c.requestSlots = make(requestSlots, c.MaxConcurrentRequests)
 	c.restapiClient = &restapi.Client{
 		Host:           c.Host,
 		ServiceAccount: c.ServiceAccount,
 		Credentials:    c.Credentials,
 		Audience:       c.Audience,
 		TokenDuration:  c.TokenDuration,
+		SkipAuth:       c.SkipAuth,
+		Limiter:        c.requestSlots,
+		RetryPolicy: restapi.RetryPolicy{
+			MaxRetries: c.MaxRetries,
+			MinBackoff: c.MinBackoff,
+			MaxBackoff: c.MaxBackoff,
+		},
 	}
 }
 
@@ -99,10 +115,25 @@ func (c *Client) GetCredentials() string {
 	return c.Credentials
 }
 
-func (c *Client) waitForAvailableSlot() {
-	c.requestSlots <- 1
+// SetRetryPolicy for the number of retries and the bounds of the backoff between retries of transient API failures
+func (c *Client) SetRetryPolicy(maxRetries int, minBackoff time.Duration, maxBackoff time.Duration) {
+	c.MaxRetries = maxRetries
+	c.MinBackoff = minBackoff
+	c.MaxBackoff = maxBackoff
+}
+
+// requestSlots limits the number of concurrent requests to MaxConcurrentRequests
+type requestSlots chan int
+
+func (s requestSlots) Acquire(ctx context.Context) error {
+	select {
+	case s <- 1:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
-func (c *Client) releaseSlot() {
-	<-c.requestSlots
+func (s requestSlots) Release() {
+	<-s
 }
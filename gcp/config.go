@@ -1,26 +1,59 @@
 package gcp
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Config is a struct for user input
 type configStuct struct {
-	Project        string
-	ServiceAccount string
-	Credentials    string
-	TokenDuration  int
+	Project              string
+	ServiceAccount       string
+	Credentials          string
+	TokenDuration        int
+	MaxRetries           int
+	MinBackoff           int
+	MaxBackoff           int
+	APIEndpoint          string
+	Audience             string
+	APIVersion           string
+	Region               string
+	DefaultBillingLabels map[string]string
 }
 
+const (
+	defaultAPIEndpoint = "https://cloudvolumesgcp-api.netapp.com"
+	defaultAPIVersion  = "v2"
+)
+
 // Client is the main function to connect to the APi
 func (c *configStuct) clientFun() (*Client, error) {
+	apiEndpoint := c.APIEndpoint
+	if apiEndpoint == "" {
+		apiEndpoint = defaultAPIEndpoint
+	}
+	apiEndpoint = strings.TrimRight(apiEndpoint, "/")
+	apiVersion := c.APIVersion
+	if apiVersion == "" {
+		apiVersion = defaultAPIVersion
+	}
+	audience := c.Audience
+	if audience == "" {
+		audience = apiEndpoint
+	}
 	client := &Client{
-		Host:     fmt.Sprintf("https://cloudvolumesgcp-api.netapp.com/v2/projects/%s/locations/", c.Project),
-		Audience: "https://cloudvolumesgcp-api.netapp.com",
+		Host:                 fmt.Sprintf("%s/%s/projects/%s/locations/", apiEndpoint, apiVersion, c.Project),
+		Audience:             strings.TrimRight(audience, "/"),
+		Region:               c.Region,
+		DefaultBillingLabels: c.DefaultBillingLabels,
 	}
 
 	client.SetServiceAccount(c.ServiceAccount)
 	client.SetCredentials(c.Credentials)
 	client.SetProjectID(c.Project)
 	client.SetTokenDuration(c.TokenDuration)
+	client.SetRetryPolicy(c.MaxRetries, time.Duration(c.MinBackoff)*time.Second, time.Duration(c.MaxBackoff)*time.Second)
 
 	return client, nil
 }
@@ -0,0 +1,88 @@
+package gcp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestConfigClientFunEndpoint(t *testing.T) {
+	cases := []struct {
+		config   configStuct
+		host     string
+		audience string
+	}{
+		{configStuct{Project: "123"}, "https://cloudvolumesgcp-api.netapp.com/v2/projects/123/locations/", "https://cloudvolumesgcp-api.netapp.com"},
+		{configStuct{Project: "123", APIEndpoint: "https://staging.example.com/", APIVersion: "v3beta"}, "https://staging.example.com/v3beta/projects/123/locations/", "https://staging.example.com"},
+		{configStuct{Project: "123", APIEndpoint: "http://localhost:8080", Audience: "https://cloudvolumesgcp-api.netapp.com/"}, "http://localhost:8080/v2/projects/123/locations/", "https://cloudvolumesgcp-api.netapp.com"},
+	}
+	for _, c := range cases {
+		client, err := c.config.clientFun()
+		if err != nil {
+			t.Fatalf("clientFun failed: %s", err)
+		}
+		if client.Host != c.host || client.Audience != c.audience {
+			t.Errorf("clientFun(%+v) = %s, %s, expected %s, %s", c.config, client.Host, client.Audience, c.host, c.audience)
+		}
+	}
+}
+
+func TestValidateHTTPURL(t *testing.T) {
+	for _, v := range []string{"https://cloudvolumesgcp-api.netapp.com", "http://localhost:8080/"} {
+		if _, errs := validateHTTPURL(v, "api_endpoint"); len(errs) != 0 {
+			t.Errorf("expected %s to be valid, got %v", v, errs)
+		}
+	}
+	for _, v := range []string{"", "cloudvolumesgcp-api.netapp.com", "ftp://example.com", "https://"} {
+		if _, errs := validateHTTPURL(v, "api_endpoint"); len(errs) == 0 {
+			t.Errorf("expected %s to be invalid", v)
+		}
+	}
+}
+
+func TestGetRegion(t *testing.T) {
+	r := resourceGCPSnapshot()
+	client := &Client{Region: "us-east4"}
+	if region, err := getRegion(schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"region": "europe-west1"}), client); err != nil || region != "europe-west1" {
+		t.Errorf("expected resource region, got %s, %v", region, err)
+	}
+	if region, err := getRegion(schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{}), client); err != nil || region != "us-east4" {
+		t.Errorf("expected provider region, got %s, %v", region, err)
+	}
+	if _, err := getRegion(schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{}), &Client{}); err == nil {
+		t.Errorf("expected an error without region")
+	}
+}
+
+func TestDefaultBillingLabels(t *testing.T) {
+	r := resourceGCPVolume()
+	set := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"billing_label": []interface{}{
+			map[string]interface{}{"key": "team", "value": "storage"},
+			map[string]interface{}{"key": "env", "value": "prod"},
+		},
+	}).Get("billing_label").(*schema.Set)
+	defaults := map[string]string{"env": "dev", "cost_center": "cc-42", "owner": "infra"}
+
+	labelValues := func(labels []billingLabel) map[string]string {
+		values := make(map[string]string)
+		for _, l := range labels {
+			values[l.Key] = l.Value
+		}
+		return values
+	}
+
+	labels := expandBillingLabel(set, defaults)
+	expected := map[string]string{"team": "storage", "env": "prod", "cost_center": "cc-42", "owner": "infra"}
+	if len(labels) != len(expected) || !reflect.DeepEqual(labelValues(labels), expected) {
+		t.Errorf("unexpected merged labels %v", labels)
+	}
+
+	labels[len(labels)-1].Value = "someone-else"
+	read := removeDefaultBillingLabels(labels, defaults, set)
+	expected = map[string]string{"team": "storage", "env": "prod", "owner": "someone-else"}
+	if len(read) != len(expected) || !reflect.DeepEqual(labelValues(read), expected) {
+		t.Errorf("unexpected labels read %v", read)
+	}
+}
@@ -0,0 +1,505 @@
+// Package cvstest provides an in-process fake of the NetApp Cloud Volumes Service API for tests.
+//
+// The fake keeps resources in memory, starts a job for every create, update, delete and action request and moves
+// resources through their lifecycle states (creating -> available, updating -> available, deleting -> deleted) as
+// the resource or its job is polled. Failures can be injected with InjectFailure.
+package cvstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// kind describes how the fake stores and returns one type of resource
+type kind struct {
+	objectType string
+	idField    string
+	stateField string
+	// wrap is true when create, update and delete responses are operations with the resource in response.AnyValue,
+	// and false when the resource is returned at the top level.
+	wrap bool
+}
+
+var kinds = map[string]kind{
+	"Volumes":               {"Volume", "volumeId", "lifeCycleState", true},
+	"DataProtectionVolumes": {"Volume", "volumeId", "lifeCycleState", true},
+	"Snapshots":             {"Snapshot", "snapshotId", "lifeCycleState", true},
+	"Backups":               {"Backup", "backupId", "lifeCycleState", true},
+	"VolumeReplications":    {"VolumeReplication", "volumeReplicationUUID", "lifeCycleState", false},
+	"Pools":                 {"Pool", "poolId", "state", true},
+	"ActiveDirectory":       {"ActiveDirectory", "UUID", "lifeCycleState", false},
+	"KmsConfig":             {"KmsConfig", "UUID", "lifeCycleState", false},
+	"BackupVault":           {"BackupVault", "UUID", "lifeCycleState", false},
+}
+
+// actionEffects are the attributes changed on a resource when an action such as Break completes
+var actionEffects = map[string]map[string]interface{}{
+	"Break":   {"mirrorState": "broken", "relationshipStatus": "idle"},
+	"Resync":  {"mirrorState": "mirrored", "relationshipStatus": "idle"},
+	"Suspend": {"mirrorState": "mirrored", "relationshipStatus": "quiesced"},
+	"Resume":  {"mirrorState": "mirrored", "relationshipStatus": "idle"},
+	"Reverse": {"mirrorState": "mirrored", "relationshipStatus": "idle"},
+}
+
+// Failure is an error injected into requests matching Method and Path
+type Failure struct {
+	// Method matches the HTTP method of the request. Empty matches any method.
+	Method string
+	// Path is matched with path.Match against the request path after the location, like "us-east4/Volumes" or "*/Volumes/*".
+	// Empty matches any path.
+	Path string
+	// StatusCode and Message are returned instead of the response, unless JobError is set.
+	StatusCode int
+	Message    string
+	// JobError accepts the request, but ends its job in error state with Message as state details.
+	// The resource is left in error state.
+	JobError bool
+	// Times is the number of matching requests which fail. Defaults to 1.
+	Times int
+}
+
+type object struct {
+	kind       kind
+	attributes map[string]interface{}
+	pending    *operation
+}
+
+type operation struct {
+	job       map[string]interface{}
+	remaining int
+	// final is the state of the resource when the operation completes. Empty for deletion.
+	final   string
+	effects map[string]interface{}
+	err     string
+}
+
+// Server is a fake Cloud Volumes Service API
+type Server struct {
+	*httptest.Server
+
+	// Steps is the number of polls of a resource or its job after which an operation completes. Defaults to 1.
+	Steps int
+
+	mu       sync.Mutex
+	objects  map[string]*object
+	jobs     map[string]*operation
+	failures []*Failure
+	requests []string
+	nextID   int
+}
+
+// NewServer starts a fake Cloud Volumes Service API. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		Steps:   1,
+		objects: map[string]*object{},
+		jobs:    map[string]*operation{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Host returns the value to use as Client.Host for the given project
+func (s *Server) Host(project string) string {
+	return fmt.Sprintf("%s/v2/projects/%s/locations/", s.URL, project)
+}
+
+// InjectFailure makes the next matching requests fail
+func (s *Server) InjectFailure(f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f.Times == 0 {
+		f.Times = 1
+	}
+	s.failures = append(s.failures, &f)
+}
+
+// Put stores a resource, for example to set up a volume which was not created through the API.
+// p is the path of the resource after the location, like "us-east4/Volumes/<volumeId>".
+func (s *Server) Put(p string, attributes map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	segments := strings.Split(p, "/")
+	k := kinds[segments[len(segments)-2]]
+	attributes = copyAttributes(attributes)
+	attributes[k.idField] = segments[len(segments)-1]
+	if _, ok := attributes["region"]; !ok {
+		attributes["region"] = segments[0]
+	}
+	if _, ok := attributes[k.stateField]; !ok {
+		attributes[k.stateField] = "available"
+	}
+	s.objects[storagePath(p)] = &object{kind: k, attributes: attributes}
+}
+
+// Get returns a copy of the resource at p, or nil if it does not exist
+func (s *Server) Get(p string) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if o, ok := s.objects[storagePath(p)]; ok {
+		return copyAttributes(o.attributes)
+	}
+	return nil
+}
+
+// Requests returns the requests received so far, as "METHOD path"
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.requests...)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/v2/projects/"), "/locations/", 2)
+	if len(parts) != 2 {
+		writeError(w, http.StatusNotFound, "Unknown path "+r.URL.Path)
+		return
+	}
+	p := strings.Trim(parts[1], "/")
+	s.requests = append(s.requests, r.Method+" "+p)
+
+	var body map[string]interface{}
+	if data, _ := ioutil.ReadAll(r.Body); len(data) > 0 {
+		if err := json.Unmarshal(data, &body); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+			return
+		}
+	}
+
+	failure := s.takeFailure(r.Method, p)
+	if failure != nil && !failure.JobError {
+		writeError(w, failure.StatusCode, failure.Message)
+		return
+	}
+
+	segments := strings.Split(p, "/")
+	region := segments[0]
+	last := segments[len(segments)-1]
+	switch {
+	case len(segments) == 2 && last == "VolumeCreationToken" && r.Method == "GET":
+		s.nextID++
+		writeJSON(w, http.StatusOK, map[string]interface{}{"creationToken": fmt.Sprintf("fake-volume-%d", s.nextID)})
+	case len(segments) == 3 && segments[1] == "Jobs" && r.Method == "GET":
+		s.getJob(w, last)
+	case isCollection(segments) && r.Method == "GET":
+		s.list(w, p)
+	case isCollection(segments) && r.Method == "POST":
+		s.create(w, region, p, body, failure)
+	case isObject(segments) && r.Method == "GET":
+		s.get(w, p)
+	case isObject(segments) && r.Method == "PUT":
+		s.update(w, p, body, failure)
+	case isObject(segments) && r.Method == "DELETE":
+		s.delete(w, p, failure)
+	case len(segments) > 2 && isObject(segments[:len(segments)-1]) && r.Method == "POST":
+		s.action(w, strings.Join(segments[:len(segments)-1], "/"), last, failure)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Unknown request %s %s", r.Method, p))
+	}
+}
+
+func isCollection(segments []string) bool {
+	_, ok := kinds[segments[len(segments)-1]]
+	return len(segments) > 1 && ok
+}
+
+func isObject(segments []string) bool {
+	return len(segments) > 2 && isCollection(segments[:len(segments)-1])
+}
+
+// storagePath maps DataProtectionVolumes to Volumes, which share one collection in CVS
+func storagePath(p string) string {
+	return strings.Replace(p, "/DataProtectionVolumes", "/Volumes", 1)
+}
+
+func (s *Server) takeFailure(method string, p string) *Failure {
+	for i, f := range s.failures {
+		if f.Method != "" && f.Method != method {
+			continue
+		}
+		if f.Path != "" {
+			if ok, _ := path.Match(f.Path, p); !ok {
+				continue
+			}
+		}
+		f.Times--
+		if f.Times <= 0 {
+			s.failures = append(s.failures[:i], s.failures[i+1:]...)
+		}
+		return f
+	}
+	return nil
+}
+
+// tick advances the pending operation of the resource at key, and completes it after Steps polls
+func (s *Server) tick(key string) {
+	o, ok := s.objects[key]
+	if !ok || o.pending == nil {
+		return
+	}
+	o.pending.remaining--
+	if o.pending.remaining > 0 {
+		return
+	}
+	op := o.pending
+	o.pending = nil
+	if op.err != "" {
+		op.job["state"] = "error"
+		op.job["stateDetails"] = op.err
+		o.attributes[o.kind.stateField] = "error"
+		o.attributes["lifeCycleStateDetails"] = op.err
+		return
+	}
+	op.job["state"] = "done"
+	if op.final == "" {
+		delete(s.objects, key)
+		return
+	}
+	for k, v := range op.effects {
+		o.attributes[k] = v
+	}
+	o.attributes[o.kind.stateField] = op.final
+	if op.final == "available" {
+		o.attributes["lifeCycleStateDetails"] = "Available for use"
+	}
+}
+
+// start begins an operation on the resource at key and returns its job
+func (s *Server) start(key string, action string, transient string, final string, failure *Failure) map[string]interface{} {
+	o := s.objects[key]
+	s.nextID++
+	job := map[string]interface{}{
+		"jobId":        fmt.Sprintf("00000000-0000-0000-0000-%012d", s.nextID),
+		"action":       action,
+		"objectType":   o.kind.objectType,
+		"objectId":     o.attributes[o.kind.idField],
+		"state":        "ongoing",
+		"stateDetails": "",
+	}
+	op := &operation{job: job, remaining: s.Steps, final: final}
+	if failure != nil {
+		op.err = failure.Message
+	}
+	if transient != "" {
+		o.attributes[o.kind.stateField] = transient
+	}
+	o.pending = op
+	s.jobs[job["jobId"].(string)] = op
+	return job
+}
+
+func (s *Server) create(w http.ResponseWriter, region string, collection string, body map[string]interface{}, failure *Failure) {
+	segments := strings.Split(collection, "/")
+	k := kinds[segments[len(segments)-1]]
+	if len(segments) > 3 {
+		parent := strings.Join(segments[:len(segments)-1], "/")
+		if _, ok := s.objects[storagePath(parent)]; !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Error describing %s - %s not found", kinds[segments[1]].objectType, kinds[segments[1]].objectType))
+			return
+		}
+	}
+	s.nextID++
+	id := fmt.Sprintf("%08d-0000-0000-0000-000000000000", s.nextID)
+	attributes := copyAttributes(body)
+	attributes[k.idField] = id
+	attributes["region"] = region
+	if len(segments) > 3 && segments[1] == "Volumes" {
+		attributes["volumeId"] = segments[2]
+	}
+	if segments[len(segments)-1] == "DataProtectionVolumes" {
+		attributes["isDataProtection"] = true
+	}
+	if segments[len(segments)-1] == "Backups" {
+		attributes["backupRegion"] = s.backupRegion(region)
+	}
+	attributes[k.stateField] = "creating"
+	key := storagePath(collection + "/" + id)
+	s.objects[key] = &object{kind: k, attributes: attributes}
+	job := s.start(key, "create", "creating", "available", failure)
+	s.writeOperation(w, s.objects[key], job)
+}
+
+func (s *Server) update(w http.ResponseWriter, p string, body map[string]interface{}, failure *Failure) {
+	key := storagePath(p)
+	o, ok := s.objects[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Error describing %s - %s not found", o.kindName(p), o.kindName(p)))
+		return
+	}
+	for k, v := range body {
+		if strings.EqualFold(k, o.kind.idField) || strings.EqualFold(k, "region") {
+			continue
+		}
+		o.attributes[o.attributeKey(k)] = v
+	}
+	job := s.start(key, "update", "updating", "available", failure)
+	s.writeOperation(w, o, job)
+}
+
+// attributeKey returns the attribute matching k case-insensitively, like the API decodes requests.
+// The storage pool requests of the provider send Go field names like "BillingLabels".
+func (o *object) attributeKey(k string) string {
+	for existing := range o.attributes {
+		if strings.EqualFold(existing, k) {
+			return existing
+		}
+	}
+	return k
+}
+
+func (s *Server) delete(w http.ResponseWriter, p string, failure *Failure) {
+	key := storagePath(p)
+	o, ok := s.objects[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Error deleting %s - %s not found", o.kindName(p), o.kindName(p)))
+		return
+	}
+	job := s.start(key, "delete", "deleting", "", failure)
+	s.writeOperation(w, o, job)
+}
+
+func (s *Server) action(w http.ResponseWriter, p string, action string, failure *Failure) {
+	key := storagePath(p)
+	o, ok := s.objects[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Error describing %s - %s not found", o.kindName(p), o.kindName(p)))
+		return
+	}
+	state, _ := o.attributes[o.kind.stateField].(string)
+	job := s.start(key, strings.ToLower(action), "", state, failure)
+	o.pending.effects = actionEffects[action]
+	if action == "Reverse" {
+		// the source and destination of a reversed replication trade places
+		o.pending.effects = copyAttributes(o.pending.effects)
+		o.pending.effects["sourceVolumeUUID"] = o.attributes["destinationVolumeUUID"]
+		o.pending.effects["destinationVolumeUUID"] = o.attributes["sourceVolumeUUID"]
+	}
+	// actions are always returned as operations
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"name":     fmt.Sprintf("operations/%s", job["jobId"]),
+		"response": map[string]interface{}{"AnyValue": map[string]interface{}{"jobs": []interface{}{job}}},
+	})
+}
+
+func (s *Server) get(w http.ResponseWriter, p string) {
+	key := storagePath(p)
+	s.tick(key)
+	o, ok := s.objects[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Error describing %s - %s not found", o.kindName(p), o.kindName(p)))
+		return
+	}
+	writeJSON(w, http.StatusOK, o.attributes)
+}
+
+func (s *Server) list(w http.ResponseWriter, collection string) {
+	segments := strings.Split(storagePath(collection), "/")
+	// the backups of all volumes of a region and the backups stored in the region are listed at <region>/Backups
+	regionBackups := len(segments) == 2 && segments[1] == "Backups"
+	var keys []string
+	for key := range s.objects {
+		objectSegments := strings.Split(key, "/")
+		if regionBackups {
+			if len(objectSegments) != 5 || objectSegments[3] != "Backups" {
+				continue
+			}
+			if segments[0] == "-" || objectSegments[0] == segments[0] || s.objects[key].attributes["backupRegion"] == segments[0] {
+				keys = append(keys, key)
+			}
+			continue
+		}
+		if segments[0] != "-" && objectSegments[0] != segments[0] {
+			continue
+		}
+		if len(objectSegments) != len(segments)+1 {
+			continue
+		}
+		if strings.Join(objectSegments[1:len(segments)], "/") != strings.Join(segments[1:], "/") {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := []interface{}{}
+	for _, key := range keys {
+		result = append(result, s.objects[key].attributes)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) getJob(w http.ResponseWriter, jobID string) {
+	op, ok := s.jobs[jobID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Error describing job - Job not found")
+		return
+	}
+	for key, o := range s.objects {
+		if o.pending == op {
+			s.tick(key)
+			break
+		}
+	}
+	writeJSON(w, http.StatusOK, op.job)
+}
+
+func (s *Server) writeOperation(w http.ResponseWriter, o *object, job map[string]interface{}) {
+	attributes := copyAttributes(o.attributes)
+	attributes["jobs"] = []interface{}{job}
+	if !o.kind.wrap {
+		writeJSON(w, http.StatusOK, attributes)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"name":     fmt.Sprintf("operations/%s", job["jobId"]),
+		"response": map[string]interface{}{"AnyValue": attributes},
+	})
+}
+
+// backupRegion returns the region new backups of volumes in region are stored in, as set by its backup vault
+func (s *Server) backupRegion(region string) string {
+	for key, o := range s.objects {
+		if strings.HasPrefix(key, region+"/Storage/BackupVault/") {
+			if backupRegion, ok := o.attributes["backupRegion"].(string); ok && backupRegion != "" {
+				return backupRegion
+			}
+		}
+	}
+	return region
+}
+
+// kindName returns the object type for error messages, also when o is nil
+func (o *object) kindName(p string) string {
+	if o != nil {
+		return o.kind.objectType
+	}
+	segments := strings.Split(p, "/")
+	return kinds[segments[len(segments)-2]].objectType
+}
+
+func copyAttributes(attributes map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(attributes))
+	for k, v := range attributes {
+		result[k] = v
+	}
+	return result
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, map[string]interface{}{"code": statusCode, "message": message})
+}
@@ -0,0 +1,128 @@
+package cvstest
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/restapi"
+)
+
+func do(t *testing.T, c *restapi.Client, method string, p string, params map[string]interface{}) (int, map[string]interface{}, error) {
+	t.Helper()
+	statusCode, body, err := c.Do(context.Background(), p, &restapi.Request{Method: method, Params: params})
+	var result map[string]interface{}
+	if err == nil {
+		if jsonErr := json.Unmarshal(body, &result); jsonErr != nil {
+			t.Fatalf("%s %s: invalid response %s", method, p, body)
+		}
+	}
+	return statusCode, result, err
+}
+
+func TestServerVolumeLifecycle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := &restapi.Client{Host: s.Host("123"), SkipAuth: true}
+
+	_, created, err := do(t, c, "POST", "us-east4/Volumes", map[string]interface{}{"name": "vol1", "creationToken": "vol1"})
+	if err != nil {
+		t.Fatalf("create failed: %s", err)
+	}
+	anyValue := created["response"].(map[string]interface{})["AnyValue"].(map[string]interface{})
+	id := anyValue["volumeId"].(string)
+	jobs := anyValue["jobs"].([]interface{})
+	if anyValue["lifeCycleState"] != "creating" || len(jobs) != 1 {
+		t.Fatalf("unexpected create response %v", created)
+	}
+	jobID := jobs[0].(map[string]interface{})["jobId"].(string)
+
+	_, job, err := do(t, c, "GET", "us-east4/Jobs/"+jobID, nil)
+	if err != nil || job["state"] != "done" || job["action"] != "create" {
+		t.Fatalf("unexpected job %v, %v", job, err)
+	}
+	if v := s.Get("us-east4/Volumes/" + id); v["lifeCycleState"] != "available" || v["lifeCycleStateDetails"] != "Available for use" {
+		t.Fatalf("unexpected volume %v", v)
+	}
+
+	if _, _, err := do(t, c, "POST", "us-east4/Volumes/"+id+"/Snapshots", map[string]interface{}{"name": "snap1"}); err != nil {
+		t.Fatalf("create snapshot failed: %s", err)
+	}
+	if _, _, err := do(t, c, "POST", "us-east4/Volumes/unknown/Snapshots", map[string]interface{}{"name": "snap1"}); !restapi.IsNotFound(err) {
+		t.Fatalf("expected not found for snapshot of unknown volume, got %v", err)
+	}
+
+	if _, _, err := do(t, c, "DELETE", "us-east4/Volumes/"+id, nil); err != nil {
+		t.Fatalf("delete failed: %s", err)
+	}
+	if v := s.Get("us-east4/Volumes/" + id); v["lifeCycleState"] != "deleting" {
+		t.Fatalf("unexpected volume %v", v)
+	}
+	if _, _, err := do(t, c, "GET", "us-east4/Volumes/"+id, nil); !restapi.IsNotFound(err) {
+		t.Fatalf("expected volume to be deleted, got %v", err)
+	}
+}
+
+func TestServerListAllRegions(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := &restapi.Client{Host: s.Host("123"), SkipAuth: true}
+	s.Put("us-east4/Volumes/a", map[string]interface{}{"name": "a"})
+	s.Put("europe-west1/Volumes/b", map[string]interface{}{"name": "b"})
+	s.Put("us-east4/Volumes/a/Snapshots/c", map[string]interface{}{"name": "c"})
+
+	_, body, err := c.Do(context.Background(), "-/Volumes", &restapi.Request{Method: "GET"})
+	if err != nil {
+		t.Fatalf("list failed: %s", err)
+	}
+	var volumes []map[string]interface{}
+	if err := json.Unmarshal(body, &volumes); err != nil || len(volumes) != 2 {
+		t.Fatalf("expected 2 volumes, got %s", body)
+	}
+}
+
+func TestServerListRegionBackups(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := &restapi.Client{Host: s.Host("123"), SkipAuth: true}
+	s.Put("us-east4/Volumes/a/Backups/b1", map[string]interface{}{"name": "b1"})
+	s.Put("us-east4/Volumes/c/Backups/b2", map[string]interface{}{"name": "b2"})
+	s.Put("europe-west1/Volumes/d/Backups/b3", map[string]interface{}{"name": "b3"})
+	s.Put("europe-west1/Volumes/d/Backups/b4", map[string]interface{}{"name": "b4", "backupRegion": "us-east4"})
+	s.Put("us-east4/Volumes/a/Snapshots/s1", map[string]interface{}{"name": "s1"})
+
+	_, body, err := c.Do(context.Background(), "us-east4/Backups", &restapi.Request{Method: "GET"})
+	if err != nil {
+		t.Fatalf("list failed: %s", err)
+	}
+	var backups []map[string]interface{}
+	if err := json.Unmarshal(body, &backups); err != nil || len(backups) != 3 {
+		t.Fatalf("expected 3 backups, got %s", body)
+	}
+}
+
+func TestServerInjectFailure(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := &restapi.Client{Host: s.Host("123"), SkipAuth: true}
+
+	s.InjectFailure(Failure{Method: "POST", Path: "*/Pools", StatusCode: 409, Message: "Pool name already in use"})
+	if _, _, err := do(t, c, "POST", "us-east4/Pools", map[string]interface{}{"name": "pool"}); !restapi.IsConflict(err) {
+		t.Fatalf("expected conflict, got %v", err)
+	}
+
+	s.InjectFailure(Failure{Method: "POST", Path: "*/Pools", JobError: true, Message: "Not enough capacity"})
+	_, created, err := do(t, c, "POST", "us-east4/Pools", map[string]interface{}{"name": "pool"})
+	if err != nil {
+		t.Fatalf("create failed: %s", err)
+	}
+	anyValue := created["response"].(map[string]interface{})["AnyValue"].(map[string]interface{})
+	jobID := anyValue["jobs"].([]interface{})[0].(map[string]interface{})["jobId"].(string)
+	_, job, _ := do(t, c, "GET", "us-east4/Jobs/"+jobID, nil)
+	if job["state"] != "error" || job["stateDetails"] != "Not enough capacity" {
+		t.Fatalf("unexpected job %v", job)
+	}
+	if v := s.Get("us-east4/Pools/" + anyValue["poolId"].(string)); v["state"] != "error" {
+		t.Fatalf("unexpected pool %v", v)
+	}
+}
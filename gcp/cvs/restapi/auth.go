@@ -0,0 +1,171 @@
+package restapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/compute/metadata"
+	credentials "cloud.google.com/go/iam/credentials/apiv1"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
+	credentialspb "google.golang.org/genproto/googleapis/iam/credentials/v1"
+)
+
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+var serviceAccountEmailRegexp = regexp.MustCompile(`^[[a-z]([-a-z0-9]*[a-z0-9])@[a-z0-9-]+\.iam\.gserviceaccount\.com$`)
+
+// impersonationURLRegexp extracts the service account email from a service_account_impersonation_url
+var impersonationURLRegexp = regexp.MustCompile(`/serviceAccounts/([^/:]+):generateAccessToken$`)
+
+// newTokenSource returns the token source for the configured authentication mode:
+// 1. JSON key or external account credentials as string - credentials
+// 2. Service Account principal name when using service account impersonation - service_account
+// 3. Absolute file path to a JSON key or external account credentials file - service_account
+// 4. Application Default Credentials, including workload identity on GKE and workload identity federation, when none is set
+func (c *Client) newTokenSource(ctx context.Context) (oauth2.TokenSource, error) {
+	if c.Credentials != "" {
+		return tokenSourceFromJSON(ctx, []byte(c.Credentials), c.Audience, c.TokenDuration)
+	}
+	if c.ServiceAccount != "" {
+		if serviceAccountEmailRegexp.MatchString(c.ServiceAccount) {
+			return newSignJWTTokenSource(ctx, c.ServiceAccount, c.Audience, c.TokenDuration, nil), nil
+		}
+		keyBytes, err := ioutil.ReadFile(c.ServiceAccount)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read service account key file  %v", err)
+		}
+		return tokenSourceFromJSON(ctx, keyBytes, c.Audience, c.TokenDuration)
+	}
+	return tokenSourceFromDefaultCredentials(ctx, c.Audience, c.TokenDuration)
+}
+
+// tokenSourceFromJSON returns a self signed JWT token source for a service account key, and a token source signing
+// JWTs through the IAM credentials API for credentials impersonating a service account, like external accounts.
+func tokenSourceFromJSON(ctx context.Context, keyBytes []byte, audience string, tokenDuration int) (oauth2.TokenSource, error) {
+	var key struct {
+		Type                           string `json:"type"`
+		ServiceAccountImpersonationURL string `json:"service_account_impersonation_url"`
+	}
+	if err := json.Unmarshal(keyBytes, &key); err != nil {
+		return nil, fmt.Errorf("Error parsing credentials: %v", err)
+	}
+	if key.Type == "service_account" {
+		tokenSource, err := google.JWTAccessTokenSourceFromJSON(keyBytes, audience)
+		if err != nil {
+			return nil, fmt.Errorf("Error building JWT access token source: %v", err)
+		}
+		return tokenSource, nil
+	}
+	email := impersonatedServiceAccount(key.ServiceAccountImpersonationURL)
+	if email == "" {
+		return nil, fmt.Errorf("Credentials of type %s must impersonate a service account to authenticate to the Cloud Volumes Service API", key.Type)
+	}
+	creds, err := google.CredentialsFromJSON(ctx, keyBytes, cloudPlatformScope)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing %s credentials: %v", key.Type, err)
+	}
+	log.Printf("Using %s credentials impersonating %s", key.Type, email)
+	return newSignJWTTokenSource(ctx, email, audience, tokenDuration, creds.TokenSource), nil
+}
+
+// tokenSourceFromDefaultCredentials uses Application Default Credentials. Without a credentials file, the service
+// account is looked up from the metadata server, which is the case on GCE, Cloud Build and GKE with workload identity.
+func tokenSourceFromDefaultCredentials(ctx context.Context, audience string, tokenDuration int) (oauth2.TokenSource, error) {
+	creds, err := google.FindDefaultCredentials(ctx, cloudPlatformScope)
+	if err != nil {
+		return nil, fmt.Errorf("Need credentials or service_account, or Application Default Credentials to get the authentication: %v", err)
+	}
+	if len(creds.JSON) > 0 {
+		return tokenSourceFromJSON(ctx, creds.JSON, audience, tokenDuration)
+	}
+	email, err := metadata.Email("default")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to get the service account of Application Default Credentials from the metadata server: %v", err)
+	}
+	log.Printf("Using Application Default Credentials of %s", email)
+	return newSignJWTTokenSource(ctx, email, audience, tokenDuration, creds.TokenSource), nil
+}
+
+// impersonatedServiceAccount returns the email in a service_account_impersonation_url, or an empty string
+func impersonatedServiceAccount(impersonationURL string) string {
+	match := impersonationURLRegexp.FindStringSubmatch(impersonationURL)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// signJWTTokenSource signs JWTs for the CVS API with the IAM credentials API, on behalf of a service account
+type signJWTTokenSource struct {
+	ctx            context.Context
+	serviceAccount string
+	audience       string
+	tokenDuration  int
+	// base authenticates the calls to the IAM credentials API. Application Default Credentials are used when nil.
+	base oauth2.TokenSource
+}
+
+func newSignJWTTokenSource(ctx context.Context, serviceAccount string, audience string, tokenDuration int, base oauth2.TokenSource) *signJWTTokenSource {
+	return &signJWTTokenSource{
+		ctx:            ctx,
+		serviceAccount: serviceAccount,
+		audience:       audience,
+		tokenDuration:  tokenDuration,
+		base:           base,
+	}
+}
+
+// Token implements oauth2.TokenSource
+func (s *signJWTTokenSource) Token() (*oauth2.Token, error) {
+	var opts []option.ClientOption
+	if s.base != nil {
+		opts = append(opts, option.WithTokenSource(s.base))
+	}
+	token, expirationTime, err := getToken(s.ctx, s.serviceAccount, s.audience, s.tokenDuration, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to get token from %s %v", s.serviceAccount, err)
+	}
+	return &oauth2.Token{AccessToken: token, TokenType: "Bearer", Expiry: time.Unix(expirationTime, 0)}, nil
+}
+
+func getToken(ctx context.Context, serviceAccountName string, audience string, tokenDuration int, opts ...option.ClientOption) (string, int64, error) {
+	log.Printf("getToken...")
+	if tokenDuration <= 0 || tokenDuration > 60 {
+		log.Print("tokenDuration is set to 60 min")
+		tokenDuration = 60
+	}
+	c, err := credentials.NewIamCredentialsClient(ctx, opts...)
+	if err != nil {
+		return "", 0, fmt.Errorf("Get iam client err: %v", err)
+	}
+	defer c.Close()
+
+	expTime := time.Now().Add(time.Minute * time.Duration(tokenDuration)).Unix()
+	jwtPayload := map[string]interface{}{
+		"iss": serviceAccountName,
+		"iat": time.Now().Unix(),
+		"aud": strings.TrimRight(audience, "/") + "/",
+		"sub": serviceAccountName,
+		"exp": expTime,
+	}
+	payloadBytes, err := json.Marshal(jwtPayload)
+	req := &credentialspb.SignJwtRequest{
+		// See https://pkg.go.dev/google.golang.org/genproto/googleapis/iam/credentials/v1#SignJwtRequest.
+		Name:    fmt.Sprintf("projects/-/serviceAccounts/%s", serviceAccountName),
+		Payload: string(payloadBytes),
+	}
+	resp, err := c.SignJwt(ctx, req)
+	if err != nil {
+		return "", 0, fmt.Errorf("signjwt failed: %v", err)
+	}
+
+	return resp.SignedJwt, expTime, nil
+}
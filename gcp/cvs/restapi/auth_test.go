@@ -0,0 +1,48 @@
+package restapi
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestImpersonatedServiceAccount(t *testing.T) {
+	cases := map[string]string{
+		"https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/cvs-admin@my-project.iam.gserviceaccount.com:generateAccessToken": "cvs-admin@my-project.iam.gserviceaccount.com",
+		"": "",
+		"https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/cvs-admin@my-project.iam.gserviceaccount.com": "",
+	}
+	for url, expected := range cases {
+		if actual := impersonatedServiceAccount(url); actual != expected {
+			t.Errorf("impersonatedServiceAccount(%q) = %q, expected %q", url, actual, expected)
+		}
+	}
+}
+
+func TestTokenSourceFromJSON(t *testing.T) {
+	externalAccount := `{
+		"type": "external_account",
+		"audience": "//iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/pool/providers/github",
+		"subject_token_type": "urn:ietf:params:oauth:token-type:jwt",
+		"token_url": "https://sts.googleapis.com/v1/token",
+		"service_account_impersonation_url": "https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/cvs-admin@my-project.iam.gserviceaccount.com:generateAccessToken",
+		"credential_source": {"file": "/var/run/token"}
+	}`
+	tokenSource, err := tokenSourceFromJSON(context.Background(), []byte(externalAccount), "https://cloudvolumesgcp-api.netapp.com", 30)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	signer, ok := tokenSource.(*signJWTTokenSource)
+	if !ok || signer.serviceAccount != "cvs-admin@my-project.iam.gserviceaccount.com" || signer.base == nil {
+		t.Errorf("unexpected token source %#v", tokenSource)
+	}
+
+	_, err = tokenSourceFromJSON(context.Background(), []byte(`{"type": "authorized_user", "client_id": "id", "client_secret": "secret", "refresh_token": "token"}`), "https://cloudvolumesgcp-api.netapp.com", 30)
+	if err == nil || !strings.Contains(err.Error(), "must impersonate a service account") {
+		t.Errorf("expected error for authorized_user credentials, got %v", err)
+	}
+
+	if _, err := tokenSourceFromJSON(context.Background(), []byte(`not json`), "https://cloudvolumesgcp-api.netapp.com", 30); err == nil {
+		t.Errorf("expected error for invalid credentials")
+	}
+}
@@ -1,48 +1,135 @@
 package restapi
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
 )
 
 // Client represents a client for interaction with a GCP REST API
 type Client struct {
-	Host                string
-	ServiceAccount      string
-	Credentials         string
-	Audience            string
-	Token               string
-	TokenDuration       int
-	TokenExpirationTime int64
-	httpClient          http.Client
+	Host           string
+	ServiceAccount string
+	Credentials    string
+	Audience       string
+	TokenDuration  int
+	RetryPolicy    RetryPolicy
+	// TokenSource provides the tokens for the Authorization header. When nil, it is derived from Credentials,
+	// ServiceAccount or Application Default Credentials on the first request.
+	TokenSource oauth2.TokenSource
+	// SkipAuth sends requests without an Authorization header, for a fake API such as cvstest.Server
+	SkipAuth bool
+	// Limiter limits the number of concurrent requests when set. A slot is only held while a request is sent and its
+	// response is read, not while waiting to retry.
+	Limiter    Limiter
+	httpClient http.Client
+	authMutex  sync.Mutex
+}
+
+// Limiter hands out a limited number of slots for concurrent requests
+type Limiter interface {
+	// Acquire waits for a free slot, or returns the context error if ctx is done first
+	Acquire(ctx context.Context) error
+	Release()
+}
+
+// getTokenSource returns the TokenSource, and creates it for the configured authentication mode on first use
+func (c *Client) getTokenSource() (oauth2.TokenSource, error) {
+	c.authMutex.Lock()
+	defer c.authMutex.Unlock()
+	if c.TokenSource == nil {
+		// the token source outlives the request, so it must not use the request context
+		tokenSource, err := c.newTokenSource(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		c.TokenSource = newCachingTokenSource(tokenSource)
+	}
+	return c.TokenSource, nil
 }
 
 // Do sends the API Request, parses the response as JSON, and returns the HTTP status code as int, the "result" value as byte
-func (c *Client) Do(baseURL string, req *Request) (int, []byte, error) {
-	httpReq, err := req.BuildHTTPReq(c, baseURL)
-	if err != nil {
-		return 0, nil, err
+// A response with a non 2xx status code is returned as a *ResponseError.
+// Transient failures are retried according to the client's RetryPolicy. The request and the waits between retries are aborted when ctx is done.
+func (c *Client) Do(ctx context.Context, baseURL string, req *Request) (int, []byte, error) {
+	for attempt := 0; ; attempt++ {
+		httpReq, err := req.BuildHTTPReq(ctx, c, baseURL)
+		if err != nil {
+			return 0, nil, err
+		}
+		log.Print("REQUEST: ", httpReq.Method, " ", httpReq.URL)
+		httpRes, res, err := c.roundTrip(ctx, httpReq)
+		if err != nil {
+			if attempt < c.RetryPolicy.MaxRetries && shouldRetryError(httpReq.Method, err) {
+				wait := c.RetryPolicy.backoff(attempt)
+				log.Printf("HTTP req failed: %v. Retrying in %v (attempt %d of %d)", err, wait, attempt+1, c.RetryPolicy.MaxRetries)
+				if err := sleep(ctx, wait); err != nil {
+					return 0, nil, err
+				}
+				continue
+			}
+			return 0, nil, err
+		}
+
+		if res == nil {
+			return 0, nil, errors.New("No result returned in REST response")
+		}
+
+		if attempt < c.RetryPolicy.MaxRetries && shouldRetryResponse(httpRes.StatusCode, res) {
+			wait := c.RetryPolicy.retryWait(httpRes.Header, attempt)
+			log.Printf("HTTP req returned %d. Retrying in %v (attempt %d of %d)", httpRes.StatusCode, wait, attempt+1, c.RetryPolicy.MaxRetries)
+			if err := sleep(ctx, wait); err != nil {
+				return 0, nil, err
+			}
+			continue
+		}
+
+		if httpRes.StatusCode < 200 || httpRes.StatusCode >= 300 {
+			return httpRes.StatusCode, res, newResponseError(httpReq, httpRes, res)
+		}
+
+		return httpRes.StatusCode, res, nil
+	}
+}
+
+// roundTrip sends the request and reads the response body, holding a Limiter slot while doing so
+func (c *Client) roundTrip(ctx context.Context, httpReq *http.Request) (*http.Response, []byte, error) {
+	if c.Limiter != nil {
+		if err := c.Limiter.Acquire(ctx); err != nil {
+			return nil, nil, err
+		}
+		defer c.Limiter.Release()
 	}
-	log.Print("REQUEST: ", httpReq.Method, " ", httpReq.URL)
+
 	httpRes, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		log.Print("HTTP req failed")
-		return 0, nil, err
+		return nil, nil, err
 	}
 
-	defer httpRes.Body.Close()
-
 	res, err := ioutil.ReadAll(httpRes.Body)
+	httpRes.Body.Close()
 	if err != nil {
 		log.Print("HTTP decoder failed")
-		return 0, nil, err
+		return nil, nil, err
 	}
+	return httpRes, res, nil
+}
 
-	if res == nil {
-		return 0, nil, errors.New("No result returned in REST response")
+// sleep waits for the given duration, or returns the context error if ctx is done first.
+func sleep(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-
-	return httpRes.StatusCode, res, nil
 }
@@ -0,0 +1,46 @@
+package restapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type recordingLimiter struct {
+	events []string
+}
+
+func (l *recordingLimiter) Acquire(ctx context.Context) error {
+	l.events = append(l.events, "acquire")
+	return nil
+}
+
+func (l *recordingLimiter) Release() {
+	l.events = append(l.events, "release")
+}
+
+func TestDoReleasesLimiterBetweenRetries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	limiter := &recordingLimiter{}
+	c := &Client{Host: server.URL + "/", SkipAuth: true, Limiter: limiter,
+		RetryPolicy: RetryPolicy{MaxRetries: 1, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}}
+	if _, _, err := c.Do(context.Background(), "us-east4/Volumes", &Request{Method: "GET"}); err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	if expected := []string{"acquire", "release", "acquire", "release"}; !reflect.DeepEqual(limiter.events, expected) {
+		t.Errorf("limiter events %v, expected %v", limiter.events, expected)
+	}
+}
@@ -1,16 +1,70 @@
 package restapi
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 )
 
+// requestIDHeaders are the response headers which may carry an ID of the request for NetApp support
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "X-Cloud-Trace-Context"}
+
 // ResponseError represents an Error to a REST API call
 type ResponseError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Name    string `json:"name"`
+	StatusCode int    `json:"-"`
+	Code       int    `json:"code"`
+	Message    string `json:"message"`
+	Name       string `json:"name"`
+	Method     string `json:"-"`
+	URL        string `json:"-"`
+	RequestID  string `json:"-"`
 }
 
 func (e *ResponseError) Error() string {
-	return fmt.Sprintf("Request returned an error. %+v", *e)
+	msg := fmt.Sprintf("%s %s returned %d: code: %d, message: %s", e.Method, e.URL, e.StatusCode, e.Code, e.Message)
+	if e.RequestID != "" {
+		msg = fmt.Sprintf("%s (request ID: %s)", msg, e.RequestID)
+	}
+	return msg
+}
+
+// newResponseError builds a ResponseError from a failed response. A body which is not a CVS error is used as message.
+func newResponseError(req *http.Request, res *http.Response, body []byte) *ResponseError {
+	e := &ResponseError{}
+	if err := json.Unmarshal(body, e); err != nil || e.Message == "" {
+		e.Message = strings.TrimSpace(string(body))
+	}
+	if e.Code == 0 {
+		e.Code = res.StatusCode
+	}
+	e.StatusCode = res.StatusCode
+	e.Method = req.Method
+	e.URL = req.URL.String()
+	for _, header := range requestIDHeaders {
+		if id := res.Header.Get(header); id != "" {
+			e.RequestID = id
+			break
+		}
+	}
+	return e
+}
+
+// IsNotFound reports whether err is a ResponseError for a missing resource
+func IsNotFound(err error) bool {
+	var e *ResponseError
+	return errors.As(err, &e) && e.StatusCode == http.StatusNotFound
+}
+
+// IsConflict reports whether err is a ResponseError for a request conflicting with the current state of a resource
+func IsConflict(err error) bool {
+	var e *ResponseError
+	return errors.As(err, &e) && e.StatusCode == http.StatusConflict
+}
+
+// IsRetryable reports whether err is a ResponseError for a transient failure, which may succeed when sent again later
+func IsRetryable(err error) bool {
+	var e *ResponseError
+	return errors.As(err, &e) && shouldRetryResponse(e.StatusCode, []byte(e.Message))
 }
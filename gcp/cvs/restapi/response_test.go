@@ -0,0 +1,52 @@
+package restapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewResponseError(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://cloudvolumesgcp-api.netapp.com/v2/projects/123/locations/us-east4/Volumes/abc", nil)
+	res := &http.Response{StatusCode: 404, Header: http.Header{}}
+	res.Header.Set("X-Request-Id", "req-1")
+
+	e := newResponseError(req, res, []byte(`{"code": 404, "message": "Error describing volume - Volume not found"}`))
+	if e.StatusCode != 404 || e.Code != 404 || e.Message != "Error describing volume - Volume not found" {
+		t.Errorf("unexpected error %#v", e)
+	}
+	if e.Method != "GET" || e.URL != req.URL.String() || e.RequestID != "req-1" {
+		t.Errorf("unexpected request details %#v", e)
+	}
+	if !strings.Contains(e.Error(), "code: 404, message: Error describing volume - Volume not found") || !strings.Contains(e.Error(), "req-1") {
+		t.Errorf("unexpected message %q", e.Error())
+	}
+
+	e = newResponseError(req, &http.Response{StatusCode: 502, Header: http.Header{}}, []byte("Bad Gateway\n"))
+	if e.StatusCode != 502 || e.Code != 502 || e.Message != "Bad Gateway" {
+		t.Errorf("unexpected error %#v", e)
+	}
+}
+
+func TestResponseErrorHelpers(t *testing.T) {
+	cases := []struct {
+		err       error
+		notFound  bool
+		conflict  bool
+		retryable bool
+	}{
+		{&ResponseError{StatusCode: 404, Message: "Volume not found"}, true, false, false},
+		{&ResponseError{StatusCode: 409, Message: "Volume is busy"}, false, true, false},
+		{&ResponseError{StatusCode: 503}, false, false, true},
+		{&ResponseError{StatusCode: 500, Message: "Cannot spawn additional jobs in us-east4-a"}, false, false, true},
+		{fmt.Errorf("reading volume: %w", &ResponseError{StatusCode: 404}), true, false, false},
+		{fmt.Errorf("code: 404, message: Volume not found"), false, false, false},
+		{nil, false, false, false},
+	}
+	for _, c := range cases {
+		if IsNotFound(c.err) != c.notFound || IsConflict(c.err) != c.conflict || IsRetryable(c.err) != c.retryable {
+			t.Errorf("unexpected result for %v: IsNotFound %v, IsConflict %v, IsRetryable %v", c.err, IsNotFound(c.err), IsConflict(c.err), IsRetryable(c.err))
+		}
+	}
+}
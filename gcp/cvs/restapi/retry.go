@@ -0,0 +1,116 @@
+package restapi
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how Client.Do retries transient failures
+type RetryPolicy struct {
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// transientErrorMessages are CVS error messages returned with a 500 status code, which go away when the request is sent again later.
+var transientErrorMessages = []string{
+	"Cannot spawn additional jobs",
+	"context deadline exceeded",
+}
+
+// shouldRetryResponse reports whether the response status code and body indicate a transient failure.
+func shouldRetryResponse(statusCode int, body []byte) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusInternalServerError:
+		for _, message := range transientErrorMessages {
+			if strings.Contains(string(body), message) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// shouldRetryError reports whether the error returned by the HTTP client for a request with the given method is transient.
+// A refused connection is retried for every method, as the request was not sent. A connection reset is only retried for
+// idempotent methods, as the server may have accepted the request already, and a POST would for example create a second volume.
+func shouldRetryError(method string, err error) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodDelete:
+		return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+	}
+	return false
+}
+
+// backoff returns the time to wait before the given retry attempt (starting at 0).
+// The wait doubles with each attempt, is bounded by MaxBackoff and randomized between half and the full value.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	minBackoff := p.minBackoff()
+	maxBackoff := p.maxBackoff()
+	wait := minBackoff
+	for i := 0; i < attempt && wait < maxBackoff; i++ {
+		wait = wait * 2
+	}
+	if wait > maxBackoff {
+		wait = maxBackoff
+	}
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(wait-half)+1))
+}
+
+func (p RetryPolicy) minBackoff() time.Duration {
+	if p.MinBackoff <= 0 {
+		return time.Second
+	}
+	return p.MinBackoff
+}
+
+func (p RetryPolicy) maxBackoff() time.Duration {
+	if p.MaxBackoff < p.minBackoff() {
+		return p.minBackoff()
+	}
+	return p.MaxBackoff
+}
+
+// retryWait returns the time to wait before retrying a response with the given headers. The Retry-After header is
+// followed when present, but never waited for longer than MaxBackoff.
+func (p RetryPolicy) retryWait(header http.Header, attempt int) time.Duration {
+	wait, ok := retryAfter(header)
+	if !ok {
+		return p.backoff(attempt)
+	}
+	if wait > p.maxBackoff() {
+		return p.maxBackoff()
+	}
+	return wait
+}
+
+// retryAfter parses the Retry-After header, which is either a number of seconds or an HTTP date.
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
@@ -0,0 +1,112 @@
+package restapi
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestShouldRetryResponse(t *testing.T) {
+	cases := []struct {
+		statusCode int
+		body       string
+		expected   bool
+	}{
+		{200, `{}`, false},
+		{404, `{"code": 404, "message": "Volume not found"}`, false},
+		{429, `{}`, true},
+		{500, `{"code": 500, "message": "Error creating volume - Cannot spawn additional jobs in us-east4-a for this network . Please wait for the ongoing jobs to finish in zone us-east4-a and try again"}`, true},
+		{500, `{"code": 500, "message": "Internal error"}`, false},
+		{502, ``, true},
+		{503, ``, true},
+		{504, ``, true},
+	}
+	for _, c := range cases {
+		if actual := shouldRetryResponse(c.statusCode, []byte(c.body)); actual != c.expected {
+			t.Errorf("shouldRetryResponse(%d, %q) = %v, expected %v", c.statusCode, c.body, actual, c.expected)
+		}
+	}
+}
+
+func TestShouldRetryError(t *testing.T) {
+	reset := fmt.Errorf("read tcp: %w", syscall.ECONNRESET)
+	refused := fmt.Errorf("dial tcp: %w", syscall.ECONNREFUSED)
+	cases := []struct {
+		method   string
+		err      error
+		expected bool
+	}{
+		{"GET", reset, true},
+		{"PUT", io.EOF, true},
+		{"DELETE", io.ErrUnexpectedEOF, true},
+		{"POST", reset, false},
+		{"POST", io.EOF, false},
+		{"POST", refused, true},
+		{"GET", fmt.Errorf("x509: certificate signed by unknown authority"), false},
+	}
+	for _, c := range cases {
+		if actual := shouldRetryError(c.method, c.err); actual != c.expected {
+			t.Errorf("shouldRetryError(%s, %v) = %v, expected %v", c.method, c.err, actual, c.expected)
+		}
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 10, MinBackoff: 2 * time.Second, MaxBackoff: 10 * time.Second}
+	cases := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{0, 2 * time.Second},
+		{1, 4 * time.Second},
+		{2, 8 * time.Second},
+		{3, 10 * time.Second},
+		{20, 10 * time.Second},
+	}
+	for _, c := range cases {
+		for i := 0; i < 100; i++ {
+			wait := policy.backoff(c.attempt)
+			if wait < c.max/2 || wait > c.max {
+				t.Fatalf("backoff(%d) = %v, expected between %v and %v", c.attempt, wait, c.max/2, c.max)
+			}
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	header := http.Header{}
+	if _, ok := retryAfter(header); ok {
+		t.Errorf("expected no Retry-After without header")
+	}
+	header.Set("Retry-After", "7")
+	if wait, ok := retryAfter(header); !ok || wait != 7*time.Second {
+		t.Errorf("retryAfter = %v, %v, expected 7s", wait, ok)
+	}
+	header.Set("Retry-After", time.Now().Add(30*time.Second).UTC().Format(http.TimeFormat))
+	if wait, ok := retryAfter(header); !ok || wait <= 0 || wait > 30*time.Second {
+		t.Errorf("retryAfter = %v, %v, expected up to 30s", wait, ok)
+	}
+	header.Set("Retry-After", "soon")
+	if _, ok := retryAfter(header); ok {
+		t.Errorf("expected invalid Retry-After to be ignored")
+	}
+}
+
+func TestRetryPolicyRetryWait(t *testing.T) {
+	p := RetryPolicy{MinBackoff: time.Second, MaxBackoff: 30 * time.Second}
+	header := http.Header{}
+	header.Set("Retry-After", "7")
+	if wait := p.retryWait(header, 0); wait != 7*time.Second {
+		t.Errorf("retryWait = %v, expected 7s", wait)
+	}
+	header.Set("Retry-After", "3600")
+	if wait := p.retryWait(header, 0); wait != 30*time.Second {
+		t.Errorf("retryWait = %v, expected Retry-After to be capped at 30s", wait)
+	}
+	if wait := p.retryWait(http.Header{}, 0); wait < 500*time.Millisecond || wait > time.Second {
+		t.Errorf("retryWait = %v without Retry-After, expected the backoff", wait)
+	}
+}
@@ -0,0 +1,39 @@
+package restapi
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// tokenRefreshMargin is how long before its expiry a cached token is replaced
+const tokenRefreshMargin = 2 * time.Minute
+
+// cachingTokenSource mints a token once and shares it across goroutines until shortly before it expires
+type cachingTokenSource struct {
+	mu     sync.Mutex
+	base   oauth2.TokenSource
+	token  *oauth2.Token
+	margin time.Duration
+	now    func() time.Time
+}
+
+func newCachingTokenSource(base oauth2.TokenSource) *cachingTokenSource {
+	return &cachingTokenSource{base: base, margin: tokenRefreshMargin, now: time.Now}
+}
+
+// Token implements oauth2.TokenSource. A token without expiry is cached forever.
+func (s *cachingTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != nil && (s.token.Expiry.IsZero() || s.now().Add(s.margin).Before(s.token.Expiry)) {
+		return s.token, nil
+	}
+	token, err := s.base.Token()
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
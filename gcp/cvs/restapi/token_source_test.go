@@ -0,0 +1,69 @@
+package restapi
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+type countingTokenSource struct {
+	mu     sync.Mutex
+	count  int
+	expiry time.Time
+}
+
+func (s *countingTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count++
+	return &oauth2.Token{AccessToken: "token", Expiry: s.expiry}, nil
+}
+
+func TestCachingTokenSourceSharesToken(t *testing.T) {
+	base := &countingTokenSource{expiry: time.Now().Add(time.Hour)}
+	s := newCachingTokenSource(base)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.Token(); err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if base.count != 1 {
+		t.Errorf("expected one token to be minted, got %d", base.count)
+	}
+}
+
+func TestCachingTokenSourceRefreshesBeforeExpiry(t *testing.T) {
+	now := time.Now()
+	base := &countingTokenSource{expiry: now.Add(10 * time.Minute)}
+	s := newCachingTokenSource(base)
+	s.now = func() time.Time { return now }
+	s.Token()
+	now = now.Add(7 * time.Minute)
+	s.Token()
+	if base.count != 1 {
+		t.Errorf("expected cached token to be used, got %d tokens", base.count)
+	}
+	now = now.Add(2 * time.Minute)
+	s.Token()
+	if base.count != 2 {
+		t.Errorf("expected token to be refreshed within %v of expiry, got %d tokens", tokenRefreshMargin, base.count)
+	}
+}
+
+func TestCachingTokenSourceWithoutExpiry(t *testing.T) {
+	base := &countingTokenSource{}
+	s := newCachingTokenSource(base)
+	s.Token()
+	s.Token()
+	if base.count != 1 {
+		t.Errorf("expected token without expiry to be cached, got %d tokens", base.count)
+	}
+}
@@ -1,12 +1,15 @@
 package gcp
 
 import (
-	"github.com/hashicorp/terraform/helper/schema"
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceGCPActiveDirectory() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceGCPActiveDirectoryRead,
+		ReadContext: dataSourceGCPActiveDirectoryRead,
 		Schema: map[string]*schema.Schema{
 			"username": {
 				Type:     schema.TypeString,
@@ -39,7 +42,8 @@ func dataSourceGCPActiveDirectory() *schema.Resource {
 			},
 			"region": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 			},
 			"uuid": {
 				Type:     schema.TypeString,
@@ -91,6 +95,14 @@ func dataSourceGCPActiveDirectory() *schema.Resource {
 	}
 }
 
-func dataSourceGCPActiveDirectoryRead(d *schema.ResourceData, meta interface{}) error {
-	return resourceGCPActiveDirectoryRead(d, meta)
+func dataSourceGCPActiveDirectoryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("region", region); err != nil {
+		return diag.Errorf("Error reading active directory region: %s", err)
+	}
+	return resourceGCPActiveDirectoryRead(ctx, d, meta)
 }
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
 func TestAccDataSourceActiveDirectory_basic(t *testing.T) {
@@ -16,7 +16,7 @@ func TestAccDataSourceActiveDirectory_basic(t *testing.T) {
 		PreCheck: func() {
 			testAccPreCheck(t)
 		},
-		Providers: testAccProviders,
+		ProviderFactories: testAccProviderFactories,
 		Steps: []resource.TestStep{
 			// if create a new active directory before creating the data source, the acceptance test passes locally but fails on Jekins server.
 			// Currently, data source is created based on existing active directory.
@@ -0,0 +1,83 @@
+package gcp
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceGCPStoragePool() *schema.Resource {
+	s := storagePoolAttributesSchema()
+	s["name"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: []string{"name", "pool_id"},
+	}
+	s["pool_id"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: []string{"name", "pool_id"},
+	}
+	s["region"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+	}
+	return &schema.Resource{
+		ReadContext: dataSourceGCPStoragePoolRead,
+		Schema:      s,
+	}
+}
+
+func dataSourceGCPStoragePoolRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("Reading storage pool: %#v", d)
+	client := meta.(*Client)
+
+	// look up the pool in all regions when neither the data source nor the provider sets region
+	region := d.Get("region").(string)
+	if region == "" {
+		region = client.Region
+	}
+	if region == "" {
+		region = "-"
+	}
+	name := d.Get("name").(string)
+	poolID := d.Get("pool_id").(string)
+	pools, err := client.getStoragePools(ctx, region)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var matches []storagePool
+	for _, pool := range pools {
+		if (poolID != "" && pool.PoolID == poolID) || (poolID == "" && pool.Name == name) {
+			matches = append(matches, pool)
+		}
+	}
+	if len(matches) == 0 {
+		if poolID != "" {
+			return diag.Errorf("No storage pool found with ID %s", poolID)
+		}
+		return diag.Errorf("No storage pool found with name %s", name)
+	}
+	if len(matches) > 1 {
+		return diag.Errorf("More than one storage pool found with name %s. Please set region or pool_id", name)
+	}
+	pool := matches[0]
+
+	allocations, err := client.getPoolAllocations(ctx, pool.Region)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for k, v := range flattenStoragePoolsItem(pool, allocations[pool.PoolID], client.Project) {
+		if err := d.Set(k, v); err != nil {
+			return diag.Errorf("Error reading storage pool %s: %s", k, err)
+		}
+	}
+	d.SetId(pool.PoolID)
+	return nil
+}
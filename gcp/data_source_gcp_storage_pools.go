@@ -0,0 +1,175 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceGCPStoragePools() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceGCPStoragePoolsRead,
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"zone": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"service_level": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"billing_label": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"value": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"storage_pools": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: storagePoolAttributesSchema(),
+				},
+			},
+		},
+	}
+}
+
+// storagePoolAttributesSchema is the schema of the storage pool attributes exposed by the storage pool data sources
+func storagePoolAttributesSchema() map[string]*schema.Schema {
+	computedString := func() *schema.Schema { return &schema.Schema{Type: schema.TypeString, Computed: true} }
+	computedInt := func() *schema.Schema { return &schema.Schema{Type: schema.TypeInt, Computed: true} }
+	computedBool := func() *schema.Schema { return &schema.Schema{Type: schema.TypeBool, Computed: true} }
+	return map[string]*schema.Schema{
+		"pool_id":                   computedString(),
+		"name":                      computedString(),
+		"region":                    computedString(),
+		"zone":                      computedString(),
+		"secondary_zone":            computedString(),
+		"network":                   computedString(),
+		"shared_vpc_project_number": computedString(),
+		"service_level":             computedString(),
+		"storage_class":             computedString(),
+		"state":                     computedString(),
+		"global_ad_access":          computedBool(),
+		"managed_pool":              computedBool(),
+		"size":                      computedInt(),
+		"allocated_size":            computedInt(),
+		"free_capacity":             computedInt(),
+		"volume_count":              computedInt(),
+		"billing_label": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"key":   computedString(),
+					"value": computedString(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceGCPStoragePoolsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("Reading storage pools: %#v", d)
+	client := meta.(*Client)
+
+	// list pools of all regions when neither the data source nor the provider sets region
+	region, err := getRegion(d, client)
+	if err != nil {
+		region = "-"
+	}
+	zone := d.Get("zone").(string)
+	serviceLevel := d.Get("service_level").(string)
+	labels := d.Get("billing_label").(*schema.Set).List()
+	pools, err := client.getStoragePools(ctx, region)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	allocations, err := client.getPoolAllocations(ctx, region)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	result := make([]interface{}, 0, len(pools))
+	for _, pool := range pools {
+		if zone != "" && pool.Zone != zone && pool.SecondaryZone != zone {
+			continue
+		}
+		if serviceLevel != "" && !strings.EqualFold(pool.ServiceLevel, serviceLevel) {
+			continue
+		}
+		matches := true
+		for _, l := range labels {
+			label := l.(map[string]interface{})
+			if !hasBillingLabel(pool.BillingLabels, label["key"].(string), label["value"].(string)) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			result = append(result, flattenStoragePoolsItem(pool, allocations[pool.PoolID], client.Project))
+		}
+	}
+	if err := d.Set("storage_pools", result); err != nil {
+		return diag.Errorf("Error reading storage pools: %s", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", client.GetProjectID(), region))
+	return nil
+}
+
+func flattenStoragePoolsItem(pool storagePool, allocation poolAllocation, project string) map[string]interface{} {
+	// pool.Network is either a network name or projects/${HOST_PROJECT_ID}/global/networks/${SHARED_VPC_NAME}
+	network := pool.Network
+	sharedVpcProjectNumber := ""
+	if nws := strings.Split(pool.Network, "/"); len(nws) == 5 {
+		network = nws[4]
+		if nws[1] != project {
+			sharedVpcProjectNumber = nws[1]
+		}
+	}
+	size := pool.SizeInBytes / GiBToBytes
+	allocated := allocation.AllocatedBytes / GiBToBytes
+	free := size - allocated
+	if free < 0 {
+		free = 0
+	}
+	return map[string]interface{}{
+		"pool_id":                   pool.PoolID,
+		"name":                      pool.Name,
+		"region":                    pool.Region,
+		"zone":                      pool.Zone,
+		"secondary_zone":            pool.SecondaryZone,
+		"network":                   network,
+		"shared_vpc_project_number": sharedVpcProjectNumber,
+		"service_level":             pool.ServiceLevel,
+		"storage_class":             pool.StorageClass,
+		"state":                     pool.State,
+		"global_ad_access":          pool.GlobalILB,
+		"managed_pool":              pool.ManagedPool,
+		"size":                      size,
+		"allocated_size":            allocated,
+		"free_capacity":             free,
+		"volume_count":              allocation.VolumeCount,
+		"billing_label":             flattenBillingLabel(pool.BillingLabels),
+	}
+}
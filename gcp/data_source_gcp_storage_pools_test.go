@@ -0,0 +1,93 @@
+package gcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/cvstest"
+)
+
+func testFakeStoragePools(srv *cvstest.Server) {
+	srv.Put("us-east4/Pools/pool-1", map[string]interface{}{"name": "pool-a", "region": "us-east4", "zone": "us-east4-a", "serviceLevel": "StandardSW", "sizeInBytes": 2048 * GiBToBytes,
+		"network": "projects/123456789/global/networks/vpc", "billingLabels": []map[string]string{{"key": "team", "value": "storage"}}})
+	srv.Put("us-east4/Pools/pool-2", map[string]interface{}{"name": "pool-b", "region": "us-east4", "zone": "us-east4-b", "secondaryZone": "us-east4-a", "serviceLevel": "ZoneRedundantStandardSW",
+		"sizeInBytes": 1024 * GiBToBytes, "network": "projects/987654321/global/networks/shared-vpc"})
+	srv.Put("europe-west1/Pools/pool-3", map[string]interface{}{"name": "pool-a", "region": "europe-west1", "zone": "europe-west1-b", "serviceLevel": "StandardSW", "sizeInBytes": 1024 * GiBToBytes})
+	srv.Put("us-east4/Volumes/vol-1", map[string]interface{}{"name": "vol-1", "poolId": "pool-1", "quotaInBytes": 512 * GiBToBytes})
+	srv.Put("us-east4/Volumes/vol-2", map[string]interface{}{"name": "vol-2", "poolId": "pool-1", "quotaInBytes": 1024 * GiBToBytes})
+	// volumes being deleted or in error state don't allocate capacity
+	srv.Put("us-east4/Volumes/vol-3", map[string]interface{}{"name": "vol-3", "poolId": "pool-1", "quotaInBytes": 256 * GiBToBytes, "lifeCycleState": "deleting"})
+	srv.Put("us-east4/Volumes/vol-4", map[string]interface{}{"name": "vol-4", "poolId": "pool-1", "quotaInBytes": 256 * GiBToBytes, "lifeCycleState": "error"})
+}
+
+func TestGCPStoragePools_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	testFakeStoragePools(srv)
+	client := testFakeClient(srv)
+
+	cases := []struct {
+		config         map[string]interface{}
+		providerRegion string
+		pools          []string
+	}{
+		{map[string]interface{}{}, "", []string{"pool-1", "pool-2", "pool-3"}},
+		{map[string]interface{}{}, "europe-west1", []string{"pool-3"}},
+		{map[string]interface{}{"region": "us-east4"}, "europe-west1", []string{"pool-1", "pool-2"}},
+		{map[string]interface{}{"region": "us-east4"}, "", []string{"pool-1", "pool-2"}},
+		{map[string]interface{}{"zone": "us-east4-a"}, "", []string{"pool-1", "pool-2"}},
+		{map[string]interface{}{"service_level": "zoneredundantstandardsw"}, "", []string{"pool-2"}},
+		{map[string]interface{}{"billing_label": []interface{}{map[string]interface{}{"key": "team", "value": "storage"}}}, "", []string{"pool-1"}},
+	}
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, dataSourceGCPStoragePools().Schema, c.config)
+		// the provider region is used when region is not set
+		client.Region = c.providerRegion
+		if diags := dataSourceGCPStoragePoolsRead(context.Background(), d, client); diags.HasError() {
+			t.Fatalf("read with %v failed: %v", c.config, diags)
+		}
+		pools := d.Get("storage_pools").([]interface{})
+		ids := make(map[string]bool)
+		for _, p := range pools {
+			ids[p.(map[string]interface{})["pool_id"].(string)] = true
+		}
+		if len(pools) != len(c.pools) {
+			t.Errorf("read with %v returned %v, expected %v", c.config, ids, c.pools)
+			continue
+		}
+		for _, id := range c.pools {
+			if !ids[id] {
+				t.Errorf("read with %v returned %v, expected %v", c.config, ids, c.pools)
+			}
+		}
+	}
+}
+
+func TestGCPStoragePool_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	testFakeStoragePools(srv)
+	client := testFakeClient(srv)
+
+	d := schema.TestResourceDataRaw(t, dataSourceGCPStoragePool().Schema, map[string]interface{}{"name": "pool-a", "region": "us-east4"})
+	if diags := dataSourceGCPStoragePoolRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if d.Id() != "pool-1" || d.Get("size") != 2048 || d.Get("allocated_size") != 1536 || d.Get("free_capacity") != 512 || d.Get("volume_count") != 2 || d.Get("network") != "vpc" {
+		t.Errorf("unexpected storage pool %v", d.State())
+	}
+
+	d = schema.TestResourceDataRaw(t, dataSourceGCPStoragePool().Schema, map[string]interface{}{"pool_id": "pool-2"})
+	if diags := dataSourceGCPStoragePoolRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if d.Get("name") != "pool-b" || d.Get("region") != "us-east4" || d.Get("free_capacity") != 1024 || d.Get("shared_vpc_project_number") != "987654321" {
+		t.Errorf("unexpected storage pool %v", d.State())
+	}
+
+	d = schema.TestResourceDataRaw(t, dataSourceGCPStoragePool().Schema, map[string]interface{}{"name": "pool-a"})
+	if diags := dataSourceGCPStoragePoolRead(context.Background(), d, client); !diags.HasError() {
+		t.Errorf("expected an error for a name used in two regions")
+	}
+}
@@ -1,14 +1,16 @@
 package gcp
 
 import (
+	"context"
 	"log"
 
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceGCPVolume() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceGCPVolumeRead,
+		ReadContext: dataSourceGCPVolumeRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -20,7 +22,8 @@ func dataSourceGCPVolume() *schema.Resource {
 			},
 			"region": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 			},
 			"protocol_types": {
 				Type:     schema.TypeList,
@@ -69,6 +72,34 @@ func dataSourceGCPVolume() *schema.Resource {
 					},
 				},
 			},
+			"backup_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"backup_policy": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"enabled": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"daily_backups_to_keep": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"weekly_backups_to_keep": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"monthly_backups_to_keep": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
 			"snapshot_policy": {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -300,23 +331,30 @@ func dataSourceGCPVolume() *schema.Resource {
 	}
 }
 
-func dataSourceGCPVolumeRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceGCPVolumeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Reading volume: %#v", d)
 	client := meta.(*Client)
 
 	volume := volumeRequest{}
 
 	volume.Name = d.Get("name").(string)
-	volume.Region = d.Get("region").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	volume.Region = region
+	if err := d.Set("region", region); err != nil {
+		return diag.Errorf("Error reading volume region: %s", err)
+	}
 
 	// Resolve volume name to volume UUID
 	var res volumeResult
-	res, err := client.getVolumeByNameOrCreationToken(volume)
+	res, err = client.getVolumeByNameOrCreationToken(ctx, volume)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Set ID to volume UUID and use normal volume read call to do parsing of attributes
 	d.SetId(res.VolumeID)
-	return resourceGCPVolumeRead(d, meta)
+	return resourceGCPVolumeRead(ctx, d, meta)
 }
@@ -0,0 +1,103 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceGCPVolumeBackups() *schema.Resource {
+	s := volumeItemsFilterSchema()
+	s["backups"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"id": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"name": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"volume_id": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"created": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"bytes_transferred": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"lifecycle_state": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"backup_region": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+	return &schema.Resource{
+		ReadContext: dataSourceGCPVolumeBackupsRead,
+		Schema:      s,
+	}
+}
+
+func dataSourceGCPVolumeBackupsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("Reading volume backups: %#v", d)
+	client := meta.(*Client)
+
+	nameFilter := expandNameFilter(d)
+	volume, err := lookupVolume(ctx, d, client)
+	if err != nil {
+		return diag.Errorf("Error reading volume backups: %s", err)
+	}
+	backups, err := client.getVolumeBackups(ctx, volume.Region, volume.VolumeID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	matches := make([]listVolumeBackupResult, 0, len(backups))
+	for _, backup := range backups {
+		if nameFilter(backup.Name) {
+			matches = append(matches, backup)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return createdAfter(matches[i].Created, matches[j].Created) })
+	if d.Get("most_recent").(bool) && len(matches) > 1 {
+		matches = matches[:1]
+	}
+
+	result := make([]interface{}, 0, len(matches))
+	for _, backup := range matches {
+		result = append(result, map[string]interface{}{
+			"id":                backup.VolumeBackupID,
+			"name":              backup.Name,
+			"volume_id":         volume.VolumeID,
+			"created":           backup.Created,
+			"bytes_transferred": backup.BytesTransferred,
+			"lifecycle_state":   backup.LifeCycleState,
+			"backup_region":     backup.storedIn(volume.Region),
+		})
+	}
+	if err := d.Set("backups", result); err != nil {
+		return diag.Errorf("Error reading volume backups: %s", err)
+	}
+	if err := d.Set("region", volume.Region); err != nil {
+		return diag.Errorf("Error reading volume backups: %s", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", volume.Region, volume.VolumeID))
+	return nil
+}
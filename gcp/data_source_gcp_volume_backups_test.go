@@ -0,0 +1,37 @@
+package gcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/cvstest"
+)
+
+func TestGCPVolumeBackups_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	srv.Put("europe-west1/Volumes/vol-1", map[string]interface{}{"name": "vol-1", "creationToken": "vol-token-1", "region": "europe-west1", "lifeCycleState": "available"})
+	srv.Put("europe-west1/Volumes/vol-1/Backups/backup-1", map[string]interface{}{"name": "weekly-1", "volumeId": "vol-1", "created": "2026-10-04T02:00:00Z", "bytesTransferred": 4096, "lifeCycleState": "available"})
+	srv.Put("europe-west1/Volumes/vol-1/Backups/backup-2", map[string]interface{}{"name": "weekly-2", "volumeId": "vol-1", "created": "2026-10-11T02:00:00Z", "bytesTransferred": 8192, "lifeCycleState": "available"})
+	srv.Put("europe-west1/Volumes/vol-1/Backups/backup-3", map[string]interface{}{"name": "manual", "volumeId": "vol-1", "created": "2026-10-12T09:30:00Z", "bytesTransferred": 1024, "lifeCycleState": "creating"})
+	client := testFakeClient(srv)
+
+	// the volume is looked up in all regions, since neither the data source nor the provider sets region
+	d := schema.TestResourceDataRaw(t, dataSourceGCPVolumeBackups().Schema, map[string]interface{}{"volume_name": "vol-1"})
+	if diags := dataSourceGCPVolumeBackupsRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if backups := d.Get("backups").([]interface{}); len(backups) != 3 || d.Get("backups.0.id") != "backup-3" || d.Get("region") != "europe-west1" {
+		t.Errorf("unexpected backups %v in %v", backups, d.Get("region"))
+	}
+
+	d = schema.TestResourceDataRaw(t, dataSourceGCPVolumeBackups().Schema, map[string]interface{}{"volume_id": "vol-1", "name_regex": "^weekly-", "most_recent": true})
+	if diags := dataSourceGCPVolumeBackupsRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if backups := d.Get("backups").([]interface{}); len(backups) != 1 || d.Get("backups.0.id") != "backup-2" || d.Get("backups.0.bytes_transferred") != 8192 ||
+		d.Get("backups.0.created") != "2026-10-11T02:00:00Z" || d.Get("backups.0.lifecycle_state") != "available" {
+		t.Errorf("unexpected backups %v", backups)
+	}
+}
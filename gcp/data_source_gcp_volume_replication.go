@@ -0,0 +1,96 @@
+package gcp
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceGCPVolumeReplication() *schema.Resource {
+	s := volumeReplicationStatusSchema()
+	s["name"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: []string{"name", "volume_replication_id"},
+	}
+	s["volume_replication_id"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: []string{"name", "volume_replication_id"},
+	}
+	s["region"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+	}
+	for _, k := range []string{"source_volume_id", "destination_volume_id", "remote_region", "endpoint_type", "policy", "schedule", "bandwidth"} {
+		s[k] = &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		}
+	}
+	return &schema.Resource{
+		ReadContext: dataSourceGCPVolumeReplicationRead,
+		Schema:      s,
+	}
+}
+
+func dataSourceGCPVolumeReplicationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("Reading volume replication: %#v", d)
+	client := meta.(*Client)
+
+	// look up the replication in all regions when neither the data source nor the provider sets region
+	region := d.Get("region").(string)
+	if region == "" {
+		region = client.Region
+	}
+	if region == "" {
+		region = "-"
+	}
+	name := d.Get("name").(string)
+	replicationID := d.Get("volume_replication_id").(string)
+	replications, err := client.getVolumeReplications(ctx, region)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var matches []volumeReplicationResult
+	for _, replica := range replications {
+		if (replicationID != "" && replica.ReplicationID == replicationID) || (replicationID == "" && replica.Name == name) {
+			matches = append(matches, replica)
+		}
+	}
+	if len(matches) == 0 {
+		if replicationID != "" {
+			return diag.Errorf("No volume replication found with ID %s", replicationID)
+		}
+		return diag.Errorf("No volume replication found with name %s", name)
+	}
+	if len(matches) > 1 {
+		return diag.Errorf("More than one volume replication found with name %s. Please set region or volume_replication_id", name)
+	}
+	res := matches[0]
+
+	attributes := flattenVolumeReplicationStatus(res)
+	attributes["name"] = res.Name
+	attributes["volume_replication_id"] = res.ReplicationID
+	attributes["region"] = res.Region
+	attributes["source_volume_id"] = res.SourceVolumeID
+	attributes["destination_volume_id"] = res.DestinationVolumeID
+	attributes["remote_region"] = res.RemoteRegion
+	attributes["endpoint_type"] = res.EndpointType
+	attributes["policy"] = res.Policy
+	attributes["schedule"] = res.Schedule
+	attributes["bandwidth"] = res.Bandwidth
+	for k, v := range attributes {
+		if err := d.Set(k, v); err != nil {
+			return diag.Errorf("Error reading volume replication %s: %s", k, err)
+		}
+	}
+	d.SetId(res.ReplicationID)
+	return nil
+}
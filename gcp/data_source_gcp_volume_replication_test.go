@@ -0,0 +1,57 @@
+package gcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/cvstest"
+)
+
+func TestGCPVolumeReplicationDataSource_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	srv.Put("us-east4/VolumeReplications/replica-1", map[string]interface{}{"name": "replica", "sourceVolumeUUID": "source-1", "destinationVolumeUUID": "destination-1",
+		"remoteRegion": "us-west2", "replicationSchedule": "hourly", "mirrorState": "mirrored", "relationshipStatus": "transferring",
+		"transferStats": map[string]interface{}{"lagTime": 4200, "lastTransferDuration": 95, "lastTransferEndTime": "2026-10-18T08:00:00Z",
+			"lastTransferSize": 1048576, "totalTransferBytes": 53687091200}})
+	srv.Put("europe-west1/VolumeReplications/replica-2", map[string]interface{}{"name": "replica", "mirrorState": "broken"})
+	srv.Put("europe-west1/VolumeReplications/replica-3", map[string]interface{}{"name": "deleting", "lifeCycleState": "deleting"})
+	client := testFakeClient(srv)
+
+	cases := []struct {
+		config map[string]interface{}
+		id     string
+	}{
+		{map[string]interface{}{"volume_replication_id": "replica-1"}, "replica-1"},
+		{map[string]interface{}{"name": "replica", "region": "europe-west1"}, "replica-2"},
+		{map[string]interface{}{"name": "replica"}, ""},
+		{map[string]interface{}{"name": "deleting"}, ""},
+	}
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, dataSourceGCPVolumeReplication().Schema, c.config)
+		diags := dataSourceGCPVolumeReplicationRead(context.Background(), d, client)
+		if c.id == "" {
+			if !diags.HasError() {
+				t.Errorf("expected read with %v to fail, got %s", c.config, d.Id())
+			}
+			continue
+		}
+		if diags.HasError() || d.Id() != c.id {
+			t.Errorf("read with %v returned %s (%v), expected %s", c.config, d.Id(), diags, c.id)
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceGCPVolumeReplication().Schema, map[string]interface{}{"name": "replica", "region": "us-east4"})
+	if diags := dataSourceGCPVolumeReplicationRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	expected := map[string]interface{}{"source_volume_id": "source-1", "destination_volume_id": "destination-1", "remote_region": "us-west2", "region": "us-east4",
+		"schedule": "hourly", "mirror_state": "mirrored", "relationship_status": "transferring", "lifecycle_state": "available", "lag_time": 4200,
+		"last_transfer_duration": 95, "last_transfer_end_time": "2026-10-18T08:00:00Z", "last_transfer_size": 1048576, "total_transfer_bytes": 53687091200}
+	for k, v := range expected {
+		if d.Get(k) != v {
+			t.Errorf("expected %s to be %v, got %v", k, v, d.Get(k))
+		}
+	}
+}
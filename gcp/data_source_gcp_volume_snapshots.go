@@ -0,0 +1,189 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceGCPVolumeSnapshots() *schema.Resource {
+	s := volumeItemsFilterSchema()
+	s["snapshots"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"id": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"name": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"volume_id": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"created": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"used_bytes": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"lifecycle_state": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+	return &schema.Resource{
+		ReadContext: dataSourceGCPVolumeSnapshotsRead,
+		Schema:      s,
+	}
+}
+
+// volumeItemsFilterSchema is the schema of the arguments selecting the volume and filtering its snapshots or backups
+func volumeItemsFilterSchema() map[string]*schema.Schema {
+	volumeArguments := []string{"volume_id", "volume_name", "creation_token"}
+	return map[string]*schema.Schema{
+		"region": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"volume_id": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			AtLeastOneOf:  volumeArguments,
+			ConflictsWith: []string{"volume_name", "creation_token"},
+		},
+		"volume_name": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			AtLeastOneOf: volumeArguments,
+		},
+		"creation_token": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			AtLeastOneOf: volumeArguments,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"name_regex": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringIsValidRegExp,
+		},
+		"most_recent": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	}
+}
+
+func dataSourceGCPVolumeSnapshotsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("Reading volume snapshots: %#v", d)
+	client := meta.(*Client)
+
+	nameFilter := expandNameFilter(d)
+	volume, err := lookupVolume(ctx, d, client)
+	if err != nil {
+		return diag.Errorf("Error reading volume snapshots: %s", err)
+	}
+	snapshots, err := client.getSnapshots(ctx, volume.Region, volume.VolumeID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	matches := make([]listSnapshotResult, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		if nameFilter(snapshot.Name) {
+			matches = append(matches, snapshot)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return createdAfter(matches[i].Created, matches[j].Created) })
+	if d.Get("most_recent").(bool) && len(matches) > 1 {
+		matches = matches[:1]
+	}
+
+	result := make([]interface{}, 0, len(matches))
+	for _, snapshot := range matches {
+		result = append(result, map[string]interface{}{
+			"id":              snapshot.SnapshotID,
+			"name":            snapshot.Name,
+			"volume_id":       volume.VolumeID,
+			"created":         snapshot.Created,
+			"used_bytes":      snapshot.UsedBytes,
+			"lifecycle_state": snapshot.LifeCycleState,
+		})
+	}
+	if err := d.Set("snapshots", result); err != nil {
+		return diag.Errorf("Error reading volume snapshots: %s", err)
+	}
+	if err := d.Set("region", volume.Region); err != nil {
+		return diag.Errorf("Error reading volume snapshots: %s", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", volume.Region, volume.VolumeID))
+	return nil
+}
+
+// lookupVolume finds the volume set by volume_id, or by volume_name and creation_token.
+// Without region on the data source or the provider, the volume is looked up in all regions.
+func lookupVolume(ctx context.Context, d *schema.ResourceData, client *Client) (volumeResult, error) {
+	region := d.Get("region").(string)
+	if region == "" {
+		region = client.Region
+	}
+	if v, ok := d.GetOk("volume_id"); ok {
+		return client.getVolumeByID(ctx, volumeRequest{Region: region, VolumeID: v.(string)})
+	}
+	if region == "" {
+		region = "-"
+	}
+	return client.getVolumeByNameOrCreationToken(ctx, volumeRequest{
+		Region:        region,
+		Name:          d.Get("volume_name").(string),
+		CreationToken: d.Get("creation_token").(string),
+	})
+}
+
+// expandNameFilter returns a function matching the names which pass the name and name_regex arguments.
+// name_regex is validated by its schema, and data sources without a name argument only filter by name_regex.
+func expandNameFilter(d *schema.ResourceData) func(string) bool {
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+	var name string
+	if v, ok := d.GetOk("name"); ok {
+		name = v.(string)
+	}
+	return func(n string) bool {
+		return (name == "" || n == name) && (nameRegex == nil || nameRegex.MatchString(n))
+	}
+}
+
+// createdAfter orders creation timestamps newest first. Timestamps which do not parse are compared as strings.
+func createdAfter(a string, b string) bool {
+	ta, errA := time.Parse(time.RFC3339, a)
+	tb, errB := time.Parse(time.RFC3339, b)
+	if errA != nil || errB != nil {
+		return a > b
+	}
+	return ta.After(tb)
+}
@@ -0,0 +1,74 @@
+package gcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/cvstest"
+)
+
+func testFakeVolumeWithSnapshots(srv *cvstest.Server) {
+	srv.Put("us-east4/Volumes/vol-1", map[string]interface{}{"name": "vol-1", "creationToken": "vol-token-1", "region": "us-east4", "lifeCycleState": "available"})
+	srv.Put("us-east4/Volumes/vol-1/Snapshots/snap-1", map[string]interface{}{"name": "snapshot-daily-2026-10-16", "volumeId": "vol-1", "created": "2026-10-16T00:10:00.000Z", "usedBytes": 1024, "lifeCycleState": "available"})
+	srv.Put("us-east4/Volumes/vol-1/Snapshots/snap-2", map[string]interface{}{"name": "snapshot-daily-2026-10-17", "volumeId": "vol-1", "created": "2026-10-17T00:10:00.000Z", "usedBytes": 2048, "lifeCycleState": "available"})
+	srv.Put("us-east4/Volumes/vol-1/Snapshots/snap-3", map[string]interface{}{"name": "before-upgrade", "volumeId": "vol-1", "created": "2026-10-15T12:00:00.000Z", "usedBytes": 512, "lifeCycleState": "available"})
+	srv.Put("us-east4/Volumes/vol-1/Snapshots/snap-4", map[string]interface{}{"name": "snapshot-daily-2026-10-18", "volumeId": "vol-1", "created": "2026-10-18T00:10:00.000Z", "lifeCycleState": "deleting"})
+}
+
+func TestGCPVolumeSnapshots_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	testFakeVolumeWithSnapshots(srv)
+	client := testFakeClient(srv)
+
+	cases := []struct {
+		config    map[string]interface{}
+		snapshots []string
+	}{
+		{map[string]interface{}{"volume_id": "vol-1"}, []string{"snap-2", "snap-1", "snap-3"}},
+		{map[string]interface{}{"volume_name": "vol-1", "region": "us-east4"}, []string{"snap-2", "snap-1", "snap-3"}},
+		{map[string]interface{}{"creation_token": "vol-token-1", "name_regex": "^snapshot-daily-"}, []string{"snap-2", "snap-1"}},
+		{map[string]interface{}{"volume_id": "vol-1", "name_regex": "^snapshot-daily-", "most_recent": true}, []string{"snap-2"}},
+		{map[string]interface{}{"volume_id": "vol-1", "name": "before-upgrade"}, []string{"snap-3"}},
+		{map[string]interface{}{"volume_id": "vol-1", "name": "unknown"}, []string{}},
+	}
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, dataSourceGCPVolumeSnapshots().Schema, c.config)
+		if diags := dataSourceGCPVolumeSnapshotsRead(context.Background(), d, client); diags.HasError() {
+			t.Fatalf("read with %v failed: %v", c.config, diags)
+		}
+		snapshots := d.Get("snapshots").([]interface{})
+		ids := make([]string, 0, len(snapshots))
+		for _, s := range snapshots {
+			ids = append(ids, s.(map[string]interface{})["id"].(string))
+		}
+		if len(ids) != len(c.snapshots) {
+			t.Errorf("read with %v returned %v, expected %v", c.config, ids, c.snapshots)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != c.snapshots[i] {
+				t.Errorf("read with %v returned %v, expected %v", c.config, ids, c.snapshots)
+				break
+			}
+		}
+		if d.Get("region") != "us-east4" || d.Id() != "us-east4/vol-1" {
+			t.Errorf("read with %v set region %v and ID %s", c.config, d.Get("region"), d.Id())
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceGCPVolumeSnapshots().Schema, map[string]interface{}{"volume_id": "vol-1", "most_recent": true})
+	if diags := dataSourceGCPVolumeSnapshotsRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if d.Get("snapshots.0.name") != "snapshot-daily-2026-10-17" || d.Get("snapshots.0.created") != "2026-10-17T00:10:00.000Z" ||
+		d.Get("snapshots.0.used_bytes") != 2048 || d.Get("snapshots.0.lifecycle_state") != "available" || d.Get("snapshots.0.volume_id") != "vol-1" {
+		t.Errorf("unexpected snapshot %v", d.Get("snapshots"))
+	}
+
+	d = schema.TestResourceDataRaw(t, dataSourceGCPVolumeSnapshots().Schema, map[string]interface{}{"volume_name": "unknown"})
+	if diags := dataSourceGCPVolumeSnapshotsRead(context.Background(), d, client); !diags.HasError() {
+		t.Errorf("expected an error for an unknown volume")
+	}
+}
@@ -0,0 +1,254 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceGCPVolumes() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceGCPVolumesRead,
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"protocol": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"NFSv3", "NFSv4", "SMB"}, false),
+			},
+			"pool_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"service_level": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"standard", "premium", "extreme"}, true),
+			},
+			"lifecycle_state": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"billing_label": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"value": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"volumes": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"region": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"size": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"service_level": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"pool_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"lifecycle_state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"protocol_types": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"mount_points": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"export": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"server": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"protocol_type": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+						"billing_label": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"key": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"value": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceGCPVolumesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("Reading volumes: %#v", d)
+	client := meta.(*Client)
+
+	filter := expandVolumesFilter(d)
+
+	// list volumes of all regions when neither the data source nor the provider sets region
+	region, err := getRegion(d, client)
+	if err != nil {
+		region = "-"
+	}
+	volumes, err := client.getVolumes(ctx, region)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	result := make([]interface{}, 0, len(volumes))
+	for _, v := range volumes {
+		if filter(v) {
+			result = append(result, flattenVolumesItem(v))
+		}
+	}
+	if err := d.Set("volumes", result); err != nil {
+		return diag.Errorf("Error reading volumes: %s", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", client.GetProjectID(), region))
+	return nil
+}
+
+// expandVolumesFilter returns a function matching the volumes which pass all filters set on the data source
+func expandVolumesFilter(d *schema.ResourceData) func(volumeResult) bool {
+	nameFilter := expandNameFilter(d)
+	protocol := d.Get("protocol").(string)
+	if protocol == "SMB" {
+		protocol = "CIFS"
+	}
+	poolID := d.Get("pool_id").(string)
+	serviceLevel := strings.ToLower(d.Get("service_level").(string))
+	lifecycleState := d.Get("lifecycle_state").(string)
+	labels := d.Get("billing_label").(*schema.Set).List()
+
+	return func(v volumeResult) bool {
+		if !nameFilter(v.Name) {
+			return false
+		}
+		if protocol != "" && !containsString(v.ProtocolTypes, protocol) {
+			return false
+		}
+		if poolID != "" && v.PoolID != poolID {
+			return false
+		}
+		if serviceLevel != "" {
+			if slevel, _ := TranslateServiceLevelAPI2State(v.ServiceLevel); slevel != serviceLevel {
+				return false
+			}
+		}
+		if lifecycleState != "" && v.LifeCycleState != lifecycleState {
+			return false
+		}
+		for _, l := range labels {
+			label := l.(map[string]interface{})
+			if !hasBillingLabel(v.BillingLabels, label["key"].(string), label["value"].(string)) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// hasBillingLabel checks for a billing label with the key, and with the value unless it is empty
+func hasBillingLabel(labels []billingLabel, key string, value string) bool {
+	for _, l := range labels {
+		if l.Key == key && (value == "" || l.Value == value) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func flattenVolumesItem(v volumeResult) map[string]interface{} {
+	slevel, _ := TranslateServiceLevelAPI2State(v.ServiceLevel)
+	protocols := make([]interface{}, 0, len(v.ProtocolTypes))
+	for _, protocol := range v.ProtocolTypes {
+		if protocol == "CIFS" {
+			protocol = "SMB"
+		}
+		protocols = append(protocols, protocol)
+	}
+	return map[string]interface{}{
+		"id":              v.VolumeID,
+		"name":            v.Name,
+		"region":          v.Region,
+		"size":            v.Size / GiBToBytes,
+		"service_level":   slevel,
+		"pool_id":         v.PoolID,
+		"lifecycle_state": v.LifeCycleState,
+		"protocol_types":  protocols,
+		"mount_points":    flattenMountPoints(v.MountPoints),
+		"billing_label":   flattenBillingLabel(v.BillingLabels),
+	}
+}
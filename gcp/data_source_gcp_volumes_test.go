@@ -0,0 +1,74 @@
+package gcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/cvstest"
+)
+
+func TestGCPVolumes_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	srv.Put("us-east4/Volumes/vol-1", map[string]interface{}{"name": "app-data", "region": "us-east4", "serviceLevel": "basic", "protocolTypes": []string{"NFSv3"}, "poolId": "pool-1",
+		"lifeCycleState": "available", "quotaInBytes": 1024 * GiBToBytes, "billingLabels": []map[string]string{{"key": "team", "value": "storage"}}})
+	srv.Put("us-east4/Volumes/vol-2", map[string]interface{}{"name": "app-logs", "region": "us-east4", "serviceLevel": "extreme", "protocolTypes": []string{"CIFS"},
+		"lifeCycleState": "available", "quotaInBytes": 2048 * GiBToBytes})
+	srv.Put("europe-west1/Volumes/vol-3", map[string]interface{}{"name": "app-data-eu", "region": "europe-west1", "serviceLevel": "basic", "protocolTypes": []string{"NFSv3", "NFSv4"},
+		"lifeCycleState": "error", "quotaInBytes": 1024 * GiBToBytes, "billingLabels": []map[string]string{{"key": "team", "value": "compute"}}})
+	client := testFakeClient(srv)
+
+	cases := []struct {
+		config  map[string]interface{}
+		volumes []string
+	}{
+		{map[string]interface{}{}, []string{"vol-1", "vol-2", "vol-3"}},
+		{map[string]interface{}{"region": "us-east4"}, []string{"vol-1", "vol-2"}},
+		{map[string]interface{}{"name_regex": "^app-data"}, []string{"vol-1", "vol-3"}},
+		{map[string]interface{}{"protocol": "SMB"}, []string{"vol-2"}},
+		{map[string]interface{}{"pool_id": "pool-1"}, []string{"vol-1"}},
+		{map[string]interface{}{"service_level": "standard"}, []string{"vol-1", "vol-3"}},
+		{map[string]interface{}{"lifecycle_state": "available", "service_level": "standard"}, []string{"vol-1"}},
+		{map[string]interface{}{"billing_label": []interface{}{map[string]interface{}{"key": "team"}}}, []string{"vol-1", "vol-3"}},
+		{map[string]interface{}{"billing_label": []interface{}{map[string]interface{}{"key": "team", "value": "compute"}}}, []string{"vol-3"}},
+	}
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, dataSourceGCPVolumes().Schema, c.config)
+		if diags := dataSourceGCPVolumesRead(context.Background(), d, client); diags.HasError() {
+			t.Fatalf("read with %v failed: %v", c.config, diags)
+		}
+		volumes := d.Get("volumes").([]interface{})
+		ids := make(map[string]bool)
+		for _, v := range volumes {
+			ids[v.(map[string]interface{})["id"].(string)] = true
+		}
+		if len(volumes) != len(c.volumes) {
+			t.Errorf("read with %v returned %v, expected %v", c.config, ids, c.volumes)
+			continue
+		}
+		for _, id := range c.volumes {
+			if !ids[id] {
+				t.Errorf("read with %v returned %v, expected %v", c.config, ids, c.volumes)
+			}
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceGCPVolumes().Schema, map[string]interface{}{"protocol": "SMB"})
+	if diags := dataSourceGCPVolumesRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if d.Get("volumes.0.service_level") != "extreme" || d.Get("volumes.0.protocol_types.0") != "SMB" || d.Get("volumes.0.size") != 2048 || d.Get("volumes.0.region") != "us-east4" {
+		t.Errorf("unexpected volume %v", d.Get("volumes.0"))
+	}
+
+	// the provider region is used when region is not set
+	client.Region = "europe-west1"
+	d = schema.TestResourceDataRaw(t, dataSourceGCPVolumes().Schema, map[string]interface{}{})
+	if diags := dataSourceGCPVolumesRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if volumes := d.Get("volumes").([]interface{}); len(volumes) != 1 || d.Get("volumes.0.id") != "vol-3" {
+		t.Errorf("expected the volumes of the provider region, got %v", volumes)
+	}
+}
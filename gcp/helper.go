@@ -1,11 +1,14 @@
 package gcp
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"log"
 	"math/rand"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 type apiErrorResponse struct {
@@ -13,24 +16,95 @@ type apiErrorResponse struct {
 	Message string `json:"message"`
 }
 
-// Check HTTP response code, return error if HTTP request is not successed.
-func apiResponseChecker(statusCode int, response []byte, funcName string) error {
+func nextRandomInt(min int, max int) int {
+	return rand.Intn(max-min) + min
+}
+
+// sleepWithContext waits for the given duration, or returns the context error if ctx is done first.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// validateHTTPURL checks that the value is an absolute http or https URL
+func validateHTTPURL(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errors = append(errors, fmt.Errorf("%q must be an http or https URL, got: %s", k, value))
+	}
+	return
+}
+
+// getRegion returns the region of the resource or data source, or the provider region when it is not set
+func getRegion(d *schema.ResourceData, client *Client) (string, error) {
+	if v, ok := d.GetOk("region"); ok {
+		return v.(string), nil
+	}
+	if client.Region != "" {
+		return client.Region, nil
+	}
+	return "", fmt.Errorf("region must be set on the resource or on the provider")
+}
 
-	if statusCode >= 300 || statusCode < 200 {
-		log.Printf("%s request failed", funcName)
-		var errorResponse apiErrorResponse
-		responseContent := bytes.NewBuffer(response).String()
-		if err := json.Unmarshal(response, &errorResponse); err != nil {
-			log.Printf("Failed to unmarshall error response from %s", funcName)
-			return fmt.Errorf(responseContent)
+// importVolumeItemState imports a snapshot or backup of a volume with an ID in <region>:<volume_id>:<id> format.
+// The volume is looked up by ID to set region, volume_id, volume_name and creation_token.
+func importVolumeItemState(format string) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+		parts := strings.Split(d.Id(), ":")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("unexpected format of ID (%s), expected %s", d.Id(), format)
+		}
+		client := meta.(*Client)
+		volume, err := client.getVolumeByID(ctx, volumeRequest{Region: parts[0], VolumeID: parts[1]})
+		if err != nil {
+			return nil, fmt.Errorf("Error importing %s, volume %s not found: %s", d.Id(), parts[1], err)
+		}
+		if err := d.Set("region", parts[0]); err != nil {
+			return nil, fmt.Errorf("Error importing %s: %s", d.Id(), err)
+		}
+		if err := setVolumeItemVolume(d, volume); err != nil {
+			return nil, fmt.Errorf("Error importing %s: %s", d.Id(), err)
 		}
-		return fmt.Errorf("code: %d, message: %s", errorResponse.Code, errorResponse.Message)
+		d.SetId(parts[2])
+		return []*schema.ResourceData{d}, nil
 	}
+}
 
+// setVolumeItemVolume sets the volume attributes of a snapshot or backup
+func setVolumeItemVolume(d *schema.ResourceData, volume volumeResult) error {
+	attributes := map[string]interface{}{
+		"volume_id":      volume.VolumeID,
+		"volume_name":    volume.Name,
+		"creation_token": volume.CreationToken,
+	}
+	for k, v := range attributes {
+		if err := d.Set(k, v); err != nil {
+			return err
+		}
+	}
 	return nil
-
 }
 
-func nextRandomInt(min int, max int) int {
-	return rand.Intn(max-min) + min
+// getVolumeItemVolumeID returns the ID of the volume of a snapshot or backup. The volume is only looked up by
+// volume_name and creation_token when volume_id is not in state yet, like for resources created before volume_id existed.
+func getVolumeItemVolumeID(ctx context.Context, d *schema.ResourceData, client *Client, region string) (string, error) {
+	if v, ok := d.GetOk("volume_id"); ok {
+		return v.(string), nil
+	}
+	volume, err := client.getVolumeByNameOrCreationToken(ctx, volumeRequest{
+		Region:        region,
+		Name:          d.Get("volume_name").(string),
+		CreationToken: d.Get("creation_token").(string),
+	})
+	if err != nil {
+		return "", err
+	}
+	return volume.VolumeID, nil
 }
@@ -0,0 +1,137 @@
+package gcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// jobPollMinInterval and jobPollMaxInterval bound the wait between two polls of a job. The wait doubles after each poll.
+var (
+	jobPollMinInterval = 5 * time.Second
+	jobPollMaxInterval = 30 * time.Second
+)
+
+// job is an asynchronous CVS operation started by a create, update or delete request
+type job struct {
+	JobID        string `json:"jobId"`
+	Action       string `json:"action"`
+	ObjectType   string `json:"objectType"`
+	ObjectID     string `json:"objectId"`
+	State        string `json:"state"`
+	StateDetails string `json:"stateDetails"`
+}
+
+// jobError is returned when a job ends in the error state
+type jobError struct {
+	JobID        string
+	Action       string
+	ObjectType   string
+	StateDetails string
+}
+
+func (e *jobError) Error() string {
+	return fmt.Sprintf("job %s (%s %s) failed: %s", e.JobID, e.Action, e.ObjectType, e.StateDetails)
+}
+
+// isJobError reports whether err is a failed job, as opposed to a failed request.
+func isJobError(err error) bool {
+	var jobErr *jobError
+	return errors.As(err, &jobErr)
+}
+
+// jobsHolder covers the response shapes which carry jobs: a top level jobs list, or jobs nested in response.AnyValue.
+type jobsHolder struct {
+	Jobs     []job `json:"jobs"`
+	Response struct {
+		AnyValue struct {
+			Jobs []job `json:"jobs"`
+		} `json:"AnyValue"`
+	} `json:"response"`
+}
+
+// extractJobs returns the jobs found in a CVS response, without duplicates.
+// Responses which don't carry jobs, or which are not JSON objects, return no jobs.
+func extractJobs(response []byte) []job {
+	if len(bytes.TrimSpace(response)) == 0 {
+		return nil
+	}
+	var holder jobsHolder
+	if err := json.Unmarshal(response, &holder); err != nil {
+		log.Printf("No jobs found in response: %s", err)
+		return nil
+	}
+	var jobs []job
+	seen := map[string]bool{}
+	for _, j := range append(holder.Jobs, holder.Response.AnyValue.Jobs...) {
+		if j.JobID == "" || seen[j.JobID] {
+			continue
+		}
+		seen[j.JobID] = true
+		jobs = append(jobs, j)
+	}
+	return jobs
+}
+
+func (c *Client) getJobByID(ctx context.Context, region string, jobID string) (job, error) {
+
+	baseURL := fmt.Sprintf("%s/Jobs/%s", region, jobID)
+
+	_, response, err := c.CallAPIMethod(ctx, "GET", baseURL, nil)
+	if err != nil {
+		log.Print("getJobByID request failed")
+		return job{}, err
+	}
+	var result job
+	if err := json.Unmarshal(response, &result); err != nil {
+		log.Print("Failed to unmarshall response from getJobByID")
+		return job{}, err
+	}
+	return result, nil
+}
+
+// waitForJob polls the job until it is done or in error state. The wait is bounded by ctx.
+func (c *Client) waitForJob(ctx context.Context, region string, jobID string) error {
+	start := time.Now()
+	interval := jobPollMinInterval
+	for {
+		if err := sleepWithContext(ctx, interval); err != nil {
+			return fmt.Errorf("timed out waiting for job %s after %v: %s", jobID, time.Since(start).Round(time.Second), err)
+		}
+		result, err := c.getJobByID(ctx, region, jobID)
+		if err != nil {
+			return err
+		}
+		switch result.State {
+		case "done":
+			log.Printf("Job %s (%s %s) done after %v", jobID, result.Action, result.ObjectType, time.Since(start).Round(time.Second))
+			return nil
+		case "error":
+			return &jobError{JobID: jobID, Action: result.Action, ObjectType: result.ObjectType, StateDetails: result.StateDetails}
+		}
+		log.Printf("Job %s (%s %s) is %s after %v", jobID, result.Action, result.ObjectType, result.State, time.Since(start).Round(time.Second))
+		interval = interval * 2
+		if interval > jobPollMaxInterval {
+			interval = jobPollMaxInterval
+		}
+	}
+}
+
+// waitForJobs waits for every job in turn, and returns the first error.
+func (c *Client) waitForJobs(ctx context.Context, region string, jobs []job) error {
+	for _, j := range jobs {
+		if err := c.waitForJob(ctx, region, j.JobID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForResponseJobs waits for the jobs started by the request which returned response.
+func (c *Client) waitForResponseJobs(ctx context.Context, region string, response []byte) error {
+	return c.waitForJobs(ctx, region, extractJobs(response))
+}
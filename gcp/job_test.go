@@ -0,0 +1,123 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/cvstest"
+)
+
+func TestExtractJobs(t *testing.T) {
+	cases := []struct {
+		response string
+		expected []string
+	}{
+		{``, nil},
+		{`[]`, nil},
+		{`{"code": 200}`, nil},
+		{`{"jobs": [{"jobId": "a", "action": "create", "state": "ongoing"}]}`, []string{"a"}},
+		{`{"response": {"AnyValue": {"volumeId": "v", "jobs": [{"jobId": "b"}, {"jobId": "c"}]}}}`, []string{"b", "c"}},
+		{`{"jobs": [{"jobId": "a"}], "response": {"AnyValue": {"jobs": [{"jobId": "a"}, {"jobId": ""}, {"jobId": "d"}]}}}`, []string{"a", "d"}},
+	}
+	for _, c := range cases {
+		jobs := extractJobs([]byte(c.response))
+		var actual []string
+		for _, j := range jobs {
+			actual = append(actual, j.JobID)
+		}
+		if fmt.Sprint(actual) != fmt.Sprint(c.expected) {
+			t.Errorf("extractJobs(%q) = %v, expected %v", c.response, actual, c.expected)
+		}
+	}
+}
+
+func TestIsJobError(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &jobError{JobID: "a", Action: "create", ObjectType: "Volume", StateDetails: "Not enough capacity"})
+	if !isJobError(err) {
+		t.Errorf("expected %v to be a job error", err)
+	}
+	if isJobError(fmt.Errorf("code: 500, message: Internal error")) {
+		t.Errorf("expected request error not to be a job error")
+	}
+}
+
+func TestWaitForResponseJobs_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	defer func(interval time.Duration) { jobPollMinInterval = interval }(jobPollMinInterval)
+	jobPollMinInterval = time.Millisecond
+	client := testFakeClient(srv)
+	ctx := context.Background()
+
+	// jobPolls returns the number of job polls since the last call
+	seen := 0
+	jobPolls := func() int {
+		requests := srv.Requests()
+		polls := 0
+		for _, request := range requests[seen:] {
+			if strings.HasPrefix(request, "GET us-east4/Jobs/") {
+				polls++
+			}
+		}
+		seen = len(requests)
+		return polls
+	}
+
+	// the job is polled until it is done
+	srv.Steps = 3
+	_, response, err := client.CallAPIMethod(ctx, "POST", "us-east4/Volumes", map[string]interface{}{"name": "vol"})
+	if err != nil {
+		t.Fatalf("create failed: %s", err)
+	}
+	if err := client.waitForResponseJobs(ctx, "us-east4", response); err != nil {
+		t.Fatalf("wait failed: %s", err)
+	}
+	if polls := jobPolls(); polls != 3 {
+		t.Errorf("expected 3 job polls, got %d", polls)
+	}
+
+	// a job in error state is a job error
+	srv.Steps = 1
+	srv.InjectFailure(cvstest.Failure{Method: "POST", Path: "*/Volumes", JobError: true, Message: "Not enough capacity"})
+	_, response, err = client.CallAPIMethod(ctx, "POST", "us-east4/Volumes", map[string]interface{}{"name": "vol"})
+	if err != nil {
+		t.Fatalf("create failed: %s", err)
+	}
+	err = client.waitForResponseJobs(ctx, "us-east4", response)
+	if !isJobError(err) || !strings.Contains(err.Error(), "Not enough capacity") {
+		t.Errorf("expected a job error, got %v", err)
+	}
+	jobPolls()
+
+	// the wait ends with the context, the job is not done yet
+	srv.Steps = 1000
+	_, response, err = client.CallAPIMethod(ctx, "POST", "us-east4/Volumes", map[string]interface{}{"name": "vol"})
+	if err != nil {
+		t.Fatalf("create failed: %s", err)
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = client.waitForResponseJobs(timeoutCtx, "us-east4", response)
+	if err == nil || isJobError(err) || !strings.Contains(err.Error(), "timed out waiting for job") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the wait to end with the context, took %v", elapsed)
+	}
+	if polls := jobPolls(); polls == 0 {
+		t.Errorf("expected the job to be polled before the timeout")
+	}
+
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := client.waitForResponseJobs(cancelledCtx, "us-east4", response); err == nil || isJobError(err) {
+		t.Errorf("expected an error for a cancelled context, got %v", err)
+	}
+	if polls := jobPolls(); polls != 0 {
+		t.Errorf("expected no job polls with a cancelled context, got %d", polls)
+	}
+}
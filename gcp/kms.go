@@ -1,6 +1,7 @@
 package gcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -18,19 +19,18 @@ type kmsConfig struct {
 	Region          string `json:"region"`
 }
 
-func (c *Client) createKMSConfig(request *kmsConfig) (kmsConfig, error) {
+func (c *Client) createKMSConfig(ctx context.Context, request *kmsConfig) (kmsConfig, error) {
 	params := structs.Map(request)
 	baseURL := fmt.Sprintf("%s/Storage/KmsConfig", request.Region)
-	log.Printf("params: %#v", params)
-	statusCode, response, err := c.CallAPIMethod("POST", baseURL, params)
+	log.Printf("params: %#v", redactParams(params))
+	_, response, err := c.CallAPIMethod(ctx, "POST", baseURL, params)
 	if err != nil {
 		log.Print("createKMSConfig request failed")
 		return kmsConfig{}, err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "createKMSConfig")
-	if responseError != nil {
-		return kmsConfig{}, responseError
+	if err := c.waitForResponseJobs(ctx, request.Region, response); err != nil {
+		return kmsConfig{}, err
 	}
 
 	var result kmsConfig
@@ -42,18 +42,14 @@ func (c *Client) createKMSConfig(request *kmsConfig) (kmsConfig, error) {
 	return result, nil
 }
 
-func (c *Client) getKMSConfig(request *kmsConfig) (kmsConfig, error) {
+func (c *Client) getKMSConfig(ctx context.Context, request *kmsConfig) (kmsConfig, error) {
 	params := structs.Map(request)
 	baseURL := fmt.Sprintf("%s/Storage/KmsConfig/%s", request.Region, request.ID)
-	statusCode, response, err := c.CallAPIMethod("GET", baseURL, params)
+	_, response, err := c.CallAPIMethod(ctx, "GET", baseURL, params)
 	if err != nil {
 		log.Print("getKMSConfig request failed")
 		return kmsConfig{}, err
 	}
-	responseError := apiResponseChecker(statusCode, response, "getKMSConfig")
-	if responseError != nil {
-		return kmsConfig{}, responseError
-	}
 	var result kmsConfig
 	if err := json.Unmarshal(response, &result); err != nil {
 		log.Print("Failed to unmarshall response from getKMSConfig")
@@ -63,18 +59,17 @@ func (c *Client) getKMSConfig(request *kmsConfig) (kmsConfig, error) {
 	return result, nil
 }
 
-func (c *Client) updateKMSConfig(request *kmsConfig) (kmsConfig, error) {
+func (c *Client) updateKMSConfig(ctx context.Context, request *kmsConfig) (kmsConfig, error) {
 	params := structs.Map(request)
 	baseURL := fmt.Sprintf("%s/Storage/KmsConfig/%s", request.Region, request.ID)
-	statusCode, response, err := c.CallAPIMethod("PUT", baseURL, params)
+	_, response, err := c.CallAPIMethod(ctx, "PUT", baseURL, params)
 	if err != nil {
 		log.Print("updateKMSConfig request failed")
 		return kmsConfig{}, err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "updateKMSConfig")
-	if responseError != nil {
-		return kmsConfig{}, responseError
+	if err := c.waitForResponseJobs(ctx, request.Region, response); err != nil {
+		return kmsConfig{}, err
 	}
 
 	var result kmsConfig
@@ -86,18 +81,17 @@ func (c *Client) updateKMSConfig(request *kmsConfig) (kmsConfig, error) {
 	return result, nil
 }
 
-func (c *Client) deleteKMSConfig(request *kmsConfig) (kmsConfig, error) {
+func (c *Client) deleteKMSConfig(ctx context.Context, request *kmsConfig) (kmsConfig, error) {
 	params := structs.Map(request)
 	baseURL := fmt.Sprintf("%s/Storage/KmsConfig/%s", request.Region, request.ID)
-	statusCode, response, err := c.CallAPIMethod("DELETE", baseURL, params)
+	_, response, err := c.CallAPIMethod(ctx, "DELETE", baseURL, params)
 	if err != nil {
 		log.Print("deleteKMSConfig request failed")
 		return kmsConfig{}, err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "deleteKMSConfig")
-	if responseError != nil {
-		return kmsConfig{}, responseError
+	if err := c.waitForResponseJobs(ctx, request.Region, response); err != nil {
+		return kmsConfig{}, err
 	}
 
 	var result kmsConfig
@@ -7,17 +7,18 @@ import (
 	"regexp"
 	"strconv"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/helper/validation"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/cloudresourcemanager/v1"
 )
 
 // Provider is the main method for NetApp GCP Terraform provider
-func Provider() terraform.ResourceProvider {
-	return &schema.Provider{
+func Provider() *schema.Provider {
+	redactLogOutput()
+	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"project": {
 				Type:        schema.TypeString,
@@ -45,6 +46,60 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("GCP_TOKEN_DURATION", nil),
 				Description: "The token duration in minutes from service account name for GCP API operations.",
 			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("GCP_MAX_RETRIES", 10),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The maximum number of retries of an API call failing with a transient error.",
+			},
+			"min_backoff": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("GCP_MIN_BACKOFF", 2),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The minimum time in seconds to wait before retrying an API call.",
+			},
+			"max_backoff": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("GCP_MAX_BACKOFF", 60),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The maximum time in seconds to wait before retrying an API call.",
+			},
+			"api_endpoint": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("GCP_API_ENDPOINT", defaultAPIEndpoint),
+				ValidateFunc: validateHTTPURL,
+				Description:  "The base URL of the Cloud Volumes Service API.",
+			},
+			"audience": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("GCP_AUDIENCE", nil),
+				ValidateFunc: validateHTTPURL,
+				Description:  "The audience of the tokens used to authenticate to the Cloud Volumes Service API. Defaults to api_endpoint.",
+			},
+			"api_version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("GCP_API_VERSION", defaultAPIVersion),
+				ValidateFunc: validation.StringMatch(regexp.MustCompile("^v[0-9]+[a-z0-9]*$"), "API version format is not correct. It should be like v2 or v2beta1."),
+				Description:  "The version of the Cloud Volumes Service API.",
+			},
+			"region": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GCP_REGION", nil),
+				Description: "The region of resources and data sources which don't set region.",
+			},
+			"default_billing_labels": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The billing labels added to every volume and storage pool, merged with their billing_label.",
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
@@ -55,19 +110,33 @@ func Provider() terraform.ResourceProvider {
 			"netapp-gcp_volume_replication": resourceGCPVolumeReplication(),
 			"netapp-gcp_kms_config":         resourceGCPKMSConfig(),
 			"netapp-gcp_storage_pool":       resourceGCPStoragePool(),
+			"netapp-gcp_backup_vault":       resourceGCPBackupVault(),
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"netapp-gcp_volume":           dataSourceGCPVolume(),
-			"netapp-gcp_active_directory": dataSourceGCPActiveDirectory(),
+			"netapp-gcp_volume":             dataSourceGCPVolume(),
+			"netapp-gcp_volumes":            dataSourceGCPVolumes(),
+			"netapp-gcp_volume_snapshots":   dataSourceGCPVolumeSnapshots(),
+			"netapp-gcp_volume_backups":     dataSourceGCPVolumeBackups(),
+			"netapp-gcp_storage_pool":       dataSourceGCPStoragePool(),
+			"netapp-gcp_storage_pools":      dataSourceGCPStoragePools(),
+			"netapp-gcp_active_directory":   dataSourceGCPActiveDirectory(),
+			"netapp-gcp_volume_replication": dataSourceGCPVolumeReplication(),
 		},
+	}
 
-		ConfigureFunc: providerConfigure,
+	p.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		client, err := providerConfigure(ctx, d)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		return client, nil
 	}
+
+	return p
 }
 
-func getProjectNumber(p string, d *schema.ResourceData) (string, error) {
-	ctx := context.Background()
+func getProjectNumber(ctx context.Context, p string, d *schema.ResourceData) (string, error) {
 	var ts *google.Credentials
 	var b []byte
 
@@ -95,6 +164,19 @@ func getProjectNumber(p string, d *schema.ResourceData) (string, error) {
 		}
 	} else if v, ok := d.GetOk("credentials"); ok {
 		b = []byte(v.(string))
+	} else {
+		// Application Default Credentials
+		cloudresourcemanagerService, err := cloudresourcemanager.NewService(ctx)
+		if err != nil {
+			log.Printf("getProjectNumber: Cannot get cloud resource manager service(%s)", err)
+			return "", err
+		}
+		resp, err := cloudresourcemanagerService.Projects.Get(p).Context(ctx).Do()
+		if err != nil {
+			log.Printf("getProjectNumber: Cannot find project number (%s)", err)
+			return "", err
+		}
+		return strconv.FormatInt(resp.ProjectNumber, 10), nil
 	}
 
 	ts, err := google.CredentialsFromJSON(ctx, b, cloudresourcemanager.CloudPlatformScope)
@@ -119,12 +201,23 @@ func getProjectNumber(p string, d *schema.ResourceData) (string, error) {
 	return strconv.FormatInt(resp.ProjectNumber, 10), nil
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, error) {
 	var projectNumber string
 	project := d.Get("project").(string)
 	serviceAccount := d.Get("service_account").(string)
 	credentials := d.Get("credentials").(string)
 	tokenDuration := d.Get("token_duration").(int)
+	maxRetries := d.Get("max_retries").(int)
+	minBackoff := d.Get("min_backoff").(int)
+	maxBackoff := d.Get("max_backoff").(int)
+	apiEndpoint := d.Get("api_endpoint").(string)
+	audience := d.Get("audience").(string)
+	apiVersion := d.Get("api_version").(string)
+	region := d.Get("region").(string)
+	defaultBillingLabels := make(map[string]string)
+	for k, v := range d.Get("default_billing_labels").(map[string]interface{}) {
+		defaultBillingLabels[k] = v.(string)
+	}
 
 	// check if project is project number or project ID
 	// project number is a string with numbers
@@ -133,7 +226,7 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	if !isProjectNumber {
 		isProjectID, err := regexp.MatchString("^[a-z][a-z0-9-]+[a-z0-9]+$", project)
 		if isProjectID {
-			projectNumber, err = getProjectNumber(project, d)
+			projectNumber, err = getProjectNumber(ctx, project, d)
 			if err != nil {
 				log.Printf("providerConfigure: Cannot find project number (%s)", err)
 				return nil, err
@@ -147,10 +240,18 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		projectNumber = project
 	}
 	config := configStuct{
-		Project:        projectNumber,
-		ServiceAccount: serviceAccount,
-		Credentials:    credentials,
-		TokenDuration:  tokenDuration,
+		Project:              projectNumber,
+		ServiceAccount:       serviceAccount,
+		Credentials:          credentials,
+		TokenDuration:        tokenDuration,
+		MaxRetries:           maxRetries,
+		MinBackoff:           minBackoff,
+		MaxBackoff:           maxBackoff,
+		APIEndpoint:          apiEndpoint,
+		Audience:             audience,
+		APIVersion:           apiVersion,
+		Region:               region,
+		DefaultBillingLabels: defaultBillingLabels,
 	}
 
 	return config.clientFun()
@@ -1,34 +1,63 @@
 package gcp
 
 import (
+	"context"
 	"testing"
 
 	"os"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/cvstest"
 )
 
-var testAccProviders map[string]terraform.ResourceProvider
+var testAccProviderFactories map[string]func() (*schema.Provider, error)
 var testAccProvider *schema.Provider
 
 func TestProvider(t *testing.T) {
-	if err := Provider().(*schema.Provider).InternalValidate(); err != nil {
+	if err := Provider().InternalValidate(); err != nil {
 		t.Fatalf("err: %s", err)
 	}
 }
 
 func TestProvider_impl(t *testing.T) {
-	var _ terraform.ResourceProvider = Provider()
+	var _ *schema.Provider = Provider()
 }
 
 func init() {
-	testAccProvider = Provider().(*schema.Provider)
-	testAccProviders = map[string]terraform.ResourceProvider{
-		"netapp-gcp": testAccProvider,
+	testAccProvider = Provider()
+	testAccProviderFactories = map[string]func() (*schema.Provider, error){
+		"netapp-gcp": func() (*schema.Provider, error) {
+			return testAccProvider, nil
+		},
 	}
 }
 
+// testFakeClient returns a client which sends requests to the fake CVS API srv, without authentication
+func testFakeClient(srv *cvstest.Server) *Client {
+	return &Client{Host: srv.Host("123456789"), Project: "123456789", SkipAuth: true}
+}
+
+// testFakeApply plans and applies config for r in-process, like terraform apply does, and returns the new state.
+// A nil config destroys the resource.
+func testFakeApply(t *testing.T, r *schema.Resource, state *terraform.InstanceState, config map[string]interface{}, meta interface{}) *terraform.InstanceState {
+	t.Helper()
+	ctx := context.Background()
+	diff := &terraform.InstanceDiff{Destroy: true}
+	if config != nil {
+		var err error
+		diff, err = r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+		if err != nil {
+			t.Fatalf("plan failed: %s", err)
+		}
+	}
+	newState, diags := r.Apply(ctx, state, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("apply failed: %v", diags)
+	}
+	return newState
+}
+
 func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("GCP_PROJECT"); v == "" {
 		t.Fatal("GCP_PROJECT must be set for acceptance tests")
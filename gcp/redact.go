@@ -0,0 +1,105 @@
+package gcp
+
+import (
+	"io"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are the parameter names, in lower case, whose values are never logged
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"credentials":   true,
+	"private_key":   true,
+	"client_secret": true,
+	"refresh_token": true,
+	"access_token":  true,
+	"token":         true,
+	"secret":        true,
+	"authorization": true,
+}
+
+var (
+	// bearerRegexp matches bearer tokens, like in Authorization headers
+	bearerRegexp = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-_.~+/]+=*`)
+	// jwtRegexp matches signed JWTs
+	jwtRegexp = regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`)
+	// privateKeyRegexp matches PEM encoded private keys, also with escaped line breaks as in JSON keys
+	privateKeyRegexp = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)
+	// sensitiveValueRegexp matches values of sensitive keys in JSON, %v and %#v output: "password":"x", password:x, Password:"x"
+	sensitiveValueRegexp = regexp.MustCompile(`(?i)(\b(?:password|credentials|private_key|client_secret|refresh_token|access_token|token|secret|authorization)"?\s*[:=]\s*)("(?:[^"\\]|\\.)*"|[^\s,}\[\])]+)`)
+)
+
+// redact masks secrets in a log message
+func redact(s string) string {
+	s = privateKeyRegexp.ReplaceAllString(s, redacted)
+	s = bearerRegexp.ReplaceAllString(s, "${1}"+redacted)
+	s = jwtRegexp.ReplaceAllString(s, redacted)
+	return sensitiveValueRegexp.ReplaceAllStringFunc(s, func(match string) string {
+		parts := sensitiveValueRegexp.FindStringSubmatch(match)
+		if strings.HasPrefix(parts[2], `"`) {
+			return parts[1] + `"` + redacted + `"`
+		}
+		return parts[1] + redacted
+	})
+}
+
+// redactParams returns a copy of API request parameters with the values of sensitive keys masked, for logging
+func redactParams(params map[string]interface{}) map[string]interface{} {
+	if params == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		if sensitiveKeys[strings.ToLower(k)] {
+			result[k] = redacted
+			continue
+		}
+		result[k] = redactValue(v)
+	}
+	return result
+}
+
+func redactValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		return redactParams(value)
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = redactValue(item)
+		}
+		return result
+	case string:
+		return redact(value)
+	}
+	return v
+}
+
+// redactingWriter masks secrets in everything written to the underlying log output
+type redactingWriter struct {
+	w io.Writer
+}
+
+func (r *redactingWriter) Write(p []byte) (int, error) {
+	if _, err := r.w.Write([]byte(redact(string(p)))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+var redactLogOutputOnce sync.Once
+
+// redactLogOutput masks secrets in all provider logging, from the standard logger and from logrus
+func redactLogOutput() {
+	redactLogOutputOnce.Do(func() {
+		log.SetOutput(&redactingWriter{w: log.Writer()})
+		logrus.SetOutput(&redactingWriter{w: logrus.StandardLogger().Out})
+	})
+}
@@ -1,23 +1,23 @@
 package gcp
 
 import (
-	"fmt"
+	"context"
 	"log"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/restapi"
 )
 
 func resourceGCPActiveDirectory() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceGCPActiveDirectoryCreate,
-		Read:   resourceGCPActiveDirectoryRead,
-		Delete: resourceGCPActiveDirectoryDelete,
-		Exists: resourceGCPActiveDirectoryExists,
-		Update: resourceGCPActiveDirectoryUpdate,
+		CreateContext: resourceGCPActiveDirectoryCreate,
+		ReadContext:   resourceGCPActiveDirectoryRead,
+		DeleteContext: resourceGCPActiveDirectoryDelete,
+		UpdateContext: resourceGCPActiveDirectoryUpdate,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: map[string]*schema.Schema{
 			//these available fields are required for create and update.
@@ -52,7 +52,8 @@ func resourceGCPActiveDirectory() *schema.Resource {
 			},
 			"region": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 			},
 			"uuid": {
 				Type:     schema.TypeString,
@@ -109,19 +110,23 @@ func resourceGCPActiveDirectory() *schema.Resource {
 	}
 }
 
-func resourceGCPActiveDirectoryCreate(d *schema.ResourceData, meta interface{}) error {
-	log.Printf("Creating active directory: %#v", d)
+func resourceGCPActiveDirectoryCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("Creating active directory: %s", d.Get("domain").(string))
 	client := meta.(*Client)
 	// check whether the AD already exists on GCP, if it exist, error out.
 	listActiveDirectory := listActiveDirectoryRequest{}
-	listActiveDirectory.Region = d.Get("region").(string)
-	existedAd, err := client.listActiveDirectoryForRegion(listActiveDirectory)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	listActiveDirectory.Region = region
+	existedAd, err := client.listActiveDirectoryForRegion(ctx, listActiveDirectory)
 	if err != nil {
 		log.Print("Error checking current active directory before creating new active directory.")
-		return err
+		return diag.FromErr(err)
 	}
 	if existedAd.UUID != "" {
-		return fmt.Errorf("Active Directory in region: \"%v\" already exists", existedAd.Region)
+		return diag.Errorf("Active Directory in region: \"%v\" already exists", existedAd.Region)
 	}
 
 	activeDirectory := operateActiveDirectoryRequest{}
@@ -137,7 +142,7 @@ func resourceGCPActiveDirectoryCreate(d *schema.ResourceData, meta interface{})
 	if v, ok := d.GetOk("site"); ok {
 		activeDirectory.Site = v.(string)
 	}
-	activeDirectory.Region = d.Get("region").(string)
+	activeDirectory.Region = region
 
 	activeDirectory.AesEncryption = d.Get("aes_encryption").(bool)
 	activeDirectory.LdapSigning = d.Get("ldap_signing").(bool)
@@ -169,146 +174,130 @@ func resourceGCPActiveDirectoryCreate(d *schema.ResourceData, meta interface{})
 
 	activeDirectory.ManagedAD = d.Get("managed_ad").(bool)
 
-	res, err := client.createActiveDirectory(&activeDirectory)
+	res, err := client.createActiveDirectory(ctx, &activeDirectory)
 	if err != nil {
 		log.Print("Error creating active directory")
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(res.UUID)
 
 	log.Printf("Created active directory in region: %v", activeDirectory.Region)
 
-	return resourceGCPActiveDirectoryRead(d, meta)
+	return resourceGCPActiveDirectoryRead(ctx, d, meta)
 }
 
-func resourceGCPActiveDirectoryRead(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPActiveDirectoryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client)
 	activeDirectory := listActiveDirectoryRequest{}
+	// without region, like on terraform import, the region is looked up by the ID
 	activeDirectory.Region = d.Get("region").(string)
 	activeDirectory.UUID = d.Id()
 	var res listActiveDirectoryResult
-	res, err := client.listActiveDirectoryForRegion(activeDirectory)
+	res, err := client.listActiveDirectoryForRegion(ctx, activeDirectory)
 	if err != nil {
-		return err
+		if restapi.IsNotFound(err) {
+			log.Printf("Active directory %s not found, removing it from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 	// Disabling, since it would fail for call from dataSourceGCPVolumeRead
 	// Unclear if this sanity check is required
 	// if res.UUID != d.id {
-	// 	return fmt.Errorf("Expected active directory with id: %v, Response contained active directory with id: %v",
+	// 	return diag.Errorf("Expected active directory with id: %v, Response contained active directory with id: %v",
 	// 		d.Get("uuid").(string), res.UUID)
 	// }
 	d.SetId(res.UUID)
 	d.Set("uuid", res.UUID)
 
 	if err := d.Set("domain", res.Domain); err != nil {
-		return fmt.Errorf("Error reading active directory domain: %s", err)
+		return diag.Errorf("Error reading active directory domain: %s", err)
 	}
 
 	if err := d.Set("net_bios", res.NetBIOS); err != nil {
-		return fmt.Errorf("Error reading active directory net_bios: %s", err)
+		return diag.Errorf("Error reading active directory net_bios: %s", err)
 	}
 
 	if err := d.Set("organizational_unit", res.OrganizationalUnit); err != nil {
-		return fmt.Errorf("Error reading active directory organizational_unit: %s", err)
+		return diag.Errorf("Error reading active directory organizational_unit: %s", err)
 	}
 
 	if err := d.Set("site", res.Site); err != nil {
-		return fmt.Errorf("Error reading active directory site: %s", err)
+		return diag.Errorf("Error reading active directory site: %s", err)
 	}
 
 	if err := d.Set("username", res.Username); err != nil {
-		return fmt.Errorf("Error reading active directory username: %s", err)
+		return diag.Errorf("Error reading active directory username: %s", err)
 	}
 
 	if err := d.Set("dns_server", res.DNS); err != nil {
-		return fmt.Errorf("Error reading active directory dns_server: %s", err)
+		return diag.Errorf("Error reading active directory dns_server: %s", err)
 	}
 
 	if err := d.Set("region", res.Region); err != nil {
-		return fmt.Errorf("Error reading active directory region: %s", err)
+		return diag.Errorf("Error reading active directory region: %s", err)
 	}
 
 	if err := d.Set("aes_encryption", res.AesEncryption); err != nil {
-		return fmt.Errorf("Error reading active directory aes_encryption: %s", err)
+		return diag.Errorf("Error reading active directory aes_encryption: %s", err)
 	}
 
 	if err := d.Set("ldap_signing", res.LdapSigning); err != nil {
-		return fmt.Errorf("Error reading active directory ldap_signing: %s", err)
+		return diag.Errorf("Error reading active directory ldap_signing: %s", err)
 	}
 
 	if err := d.Set("allow_local_nfs_users_with_ldap", res.AllowLocalNFSUsersWithLdap); err != nil {
-		return fmt.Errorf("Error reading active directory allow_local_nfs_users_with_ldap: %s", err)
+		return diag.Errorf("Error reading active directory allow_local_nfs_users_with_ldap: %s", err)
 	}
 
 	if err := d.Set("security_operators", res.SecurityOperators); err != nil {
-		return fmt.Errorf("Error reading active directory security_operators: %s", err)
+		return diag.Errorf("Error reading active directory security_operators: %s", err)
 	}
 
 	if err := d.Set("backup_operators", res.BackupOperators); err != nil {
-		return fmt.Errorf("Error reading active directory backup_operators: %s", err)
+		return diag.Errorf("Error reading active directory backup_operators: %s", err)
 	}
 
 	if err := d.Set("kdc_ip", res.KdcIP); err != nil {
-		return fmt.Errorf("Error reading active directory kdc_ip: %s", err)
+		return diag.Errorf("Error reading active directory kdc_ip: %s", err)
 	}
 
 	if err := d.Set("connection_type", res.Label); err != nil {
-		return fmt.Errorf("Error reading active directory connection_type: %s", err)
+		return diag.Errorf("Error reading active directory connection_type: %s", err)
 	}
 
 	if err := d.Set("ad_server", res.AdName); err != nil {
-		return fmt.Errorf("Error reading active directory ad_server: %s", err)
+		return diag.Errorf("Error reading active directory ad_server: %s", err)
 	}
 
 	if err := d.Set("managed_ad", res.ManagedAD); err != nil {
-		return fmt.Errorf("Error reading active directory managed_ad: %s", err)
+		return diag.Errorf("Error reading active directory managed_ad: %s", err)
 	}
 	return nil
 }
 
-func resourceGCPActiveDirectoryDelete(d *schema.ResourceData, meta interface{}) error {
-	log.Printf("Deleting active directory: %#v", d)
+func resourceGCPActiveDirectoryDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("Deleting active directory: %s", d.Id())
 	client := meta.(*Client)
 	activeDirectory := deleteActiveDirectoryRequest{}
-	activeDirectory.Region = d.Get("region").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	activeDirectory.Region = region
 	activeDirectory.UUID = d.Get("uuid").(string)
-	deleteErr := client.deleteActiveDirectory(activeDirectory)
-	if deleteErr != nil {
-		return deleteErr
+	deleteErr := client.deleteActiveDirectory(ctx, activeDirectory)
+	if deleteErr != nil && !restapi.IsNotFound(deleteErr) {
+		return diag.FromErr(deleteErr)
 	}
 	d.SetId("")
 
 	return nil
 }
 
-func resourceGCPActiveDirectoryExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	log.Printf("Checking existence of active directory: %#v", d)
-	client := meta.(*Client)
-	activeDirectory := listActiveDirectoryRequest{}
-	activeDirectory.UUID = d.Id()
-	activeDirectory.Region = d.Get("region").(string)
-	var res listActiveDirectoryResult
-	res, err := client.listActiveDirectoryForRegion(activeDirectory)
-	if err != nil {
-		if err, ok := err.(*restapi.ResponseError); ok {
-			if err.Name == "xUnknown" {
-				d.SetId("")
-				return false, nil
-			}
-			return false, err
-		}
-		return false, err
-	}
-	if res.UUID != activeDirectory.UUID {
-		d.SetId("")
-		return false, nil
-	}
-
-	return true, err
-}
-
-func resourceGCPActiveDirectoryUpdate(d *schema.ResourceData, meta interface{}) error {
-	log.Printf("Checking existence of active directory: %#v", d)
+func resourceGCPActiveDirectoryUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("Updating active directory: %s", d.Id())
 	client := meta.(*Client)
 	activeDirectory := operateActiveDirectoryRequest{}
 	// all of the following are required for API: update.
@@ -319,7 +308,11 @@ func resourceGCPActiveDirectoryUpdate(d *schema.ResourceData, meta interface{})
 	activeDirectory.NetBIOS = d.Get("net_bios").(string)
 	activeDirectory.OrganizationalUnit = d.Get("organizational_unit").(string)
 	activeDirectory.Site = d.Get("site").(string)
-	activeDirectory.Region = d.Get("region").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	activeDirectory.Region = region
 	activeDirectory.UUID = d.Get("uuid").(string)
 	activeDirectory.Label = d.Get("connection_type").(string)
 
@@ -355,9 +348,9 @@ func resourceGCPActiveDirectoryUpdate(d *schema.ResourceData, meta interface{})
 
 	activeDirectory.ManagedAD = d.Get("managed_ad").(bool)
 
-	err := client.updateActiveDirectory(activeDirectory)
+	err = client.updateActiveDirectory(ctx, activeDirectory)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	return resourceGCPActiveDirectoryRead(d, meta)
+	return resourceGCPActiveDirectoryRead(ctx, d, meta)
 }
@@ -1,11 +1,12 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccActiveDirectory_basic(t *testing.T) {
@@ -17,8 +18,8 @@ func TestAccActiveDirectory_basic(t *testing.T) {
 		PreCheck: func() {
 			testAccPreCheck(t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckGCPActiveDirectoryDestroy,
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGCPActiveDirectoryDestroy,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccActiveDirectoryConfigCreate(),
@@ -58,7 +59,7 @@ func testAccCheckGCPActiveDirectoryDestroy(state *terraform.State) error {
 		if rs.Type != "netapp-gcp_active_directory" {
 			continue
 		}
-		response, err := client.listActiveDirectoryForRegion(listActiveDirectoryRequest{
+		response, err := client.listActiveDirectoryForRegion(context.Background(), listActiveDirectoryRequest{
 			UUID:   rs.Primary.ID,
 			Region: rs.Primary.Attributes["region"],
 		})
@@ -83,7 +84,7 @@ func testAccCheckGCPActiveDirectoryExists(name string, activeDirectory *listActi
 		if rs.Primary.ID == "" {
 			return fmt.Errorf("No active directory ID is set")
 		}
-		response, err := client.listActiveDirectoryForRegion(listActiveDirectoryRequest{
+		response, err := client.listActiveDirectoryForRegion(context.Background(), listActiveDirectoryRequest{
 			UUID:   rs.Primary.ID,
 			Region: rs.Primary.Attributes["region"],
 		})
@@ -0,0 +1,161 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/restapi"
+)
+
+func resourceGCPBackupVault() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceGCPBackupVaultCreate,
+		ReadContext:   resourceGCPBackupVaultRead,
+		DeleteContext: resourceGCPBackupVaultDelete,
+		UpdateContext: resourceGCPBackupVaultUpdate,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceGCPBackupVaultImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			// backups of the volumes in region are stored in backup_region
+			"backup_region": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"lifecycle_state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceGCPBackupVaultCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("Creating backup vault: %#v", d)
+	client := meta.(*Client)
+
+	vault := backupVault{}
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	vault.Region = region
+	vault.BackupRegion = d.Get("backup_region").(string)
+
+	res, err := client.createBackupVault(ctx, &vault)
+	if err != nil {
+		log.Print("Error creating backup vault")
+		return diag.FromErr(err)
+	}
+	d.SetId(res.ID)
+
+	log.Printf("Created backup vault in region: %v", vault.Region)
+
+	return resourceGCPBackupVaultRead(ctx, d, meta)
+}
+
+func resourceGCPBackupVaultRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("Reading backup vault: %#v", d)
+	client := meta.(*Client)
+	vault := backupVault{}
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	vault.Region = region
+	vault.ID = d.Id()
+	res, err := client.getBackupVault(ctx, &vault)
+	if err != nil {
+		if restapi.IsNotFound(err) {
+			log.Printf("Backup vault %s not found, removing it from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	if res.ID != d.Id() {
+		return diag.Errorf("Expected backup vault with id: %v, Response contained backup vault with id: %v",
+			d.Id(), res.ID)
+	}
+
+	if err := d.Set("region", res.Region); err != nil {
+		return diag.Errorf("Error reading backup vault region: %s", err)
+	}
+
+	if err := d.Set("backup_region", res.BackupRegion); err != nil {
+		return diag.Errorf("Error reading backup vault backup_region: %s", err)
+	}
+
+	if err := d.Set("lifecycle_state", res.LifeCycleState); err != nil {
+		return diag.Errorf("Error reading backup vault lifecycle_state: %s", err)
+	}
+
+	return nil
+}
+
+func resourceGCPBackupVaultUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("Updating backup vault: %#v", d)
+	client := meta.(*Client)
+	vault := backupVault{}
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	vault.Region = region
+	vault.ID = d.Id()
+	vault.BackupRegion = d.Get("backup_region").(string)
+
+	_, err = client.updateBackupVault(ctx, &vault)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceGCPBackupVaultRead(ctx, d, meta)
+}
+
+func resourceGCPBackupVaultDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("Deleting backup vault: %#v", d)
+	client := meta.(*Client)
+	vault := backupVault{}
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	vault.Region = region
+	vault.ID = d.Id()
+	deleteErr := client.deleteBackupVault(ctx, &vault)
+	if deleteErr != nil && !restapi.IsNotFound(deleteErr) {
+		return diag.FromErr(deleteErr)
+	}
+	d.SetId("")
+
+	return nil
+}
+
+// resourceGCPBackupVaultImport imports a backup vault with an ID in <region>:<id> format
+func resourceGCPBackupVaultImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("unexpected format of ID (%s), expected <region>:<id>", d.Id())
+	}
+	if err := d.Set("region", parts[0]); err != nil {
+		return nil, fmt.Errorf("Error importing %s: %s", d.Id(), err)
+	}
+	d.SetId(parts[1])
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,169 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/cvstest"
+)
+
+func TestAccBackupVault_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGCPBackupVaultDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBackupVaultConfig("us-west2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGCPBackupVaultExists("netapp-gcp_backup_vault.terraform-acceptance-test-1"),
+					resource.TestCheckResourceAttr("netapp-gcp_backup_vault.terraform-acceptance-test-1", "region", "us-east4"),
+					resource.TestCheckResourceAttr("netapp-gcp_backup_vault.terraform-acceptance-test-1", "backup_region", "us-west2"),
+				),
+			},
+			{
+				Config: testAccBackupVaultConfig("us-central1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGCPBackupVaultExists("netapp-gcp_backup_vault.terraform-acceptance-test-1"),
+					resource.TestCheckResourceAttr("netapp-gcp_backup_vault.terraform-acceptance-test-1", "backup_region", "us-central1"),
+				),
+			},
+			{
+				ResourceName:      "netapp-gcp_backup_vault.terraform-acceptance-test-1",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs := s.RootModule().Resources["netapp-gcp_backup_vault.terraform-acceptance-test-1"]
+					return fmt.Sprintf("%s:%s", rs.Primary.Attributes["region"], rs.Primary.ID), nil
+				},
+			},
+		},
+	})
+}
+
+func testAccCheckGCPBackupVaultDestroy(state *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range state.RootModule().Resources {
+		if rs.Type != "netapp-gcp_backup_vault" {
+			continue
+		}
+		response, err := client.getBackupVault(context.Background(), &backupVault{ID: rs.Primary.ID, Region: rs.Primary.Attributes["region"]})
+		if err == nil && response.ID != "" {
+			return fmt.Errorf("Backup vault (%s) still exists", response.ID)
+		}
+	}
+	return nil
+}
+
+func testAccCheckGCPBackupVaultExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No backup vault ID is set")
+		}
+		response, err := client.getBackupVault(context.Background(), &backupVault{ID: rs.Primary.ID, Region: rs.Primary.Attributes["region"]})
+		if err != nil {
+			return err
+		}
+		if response.ID != rs.Primary.ID {
+			return fmt.Errorf("Resource ID and backup vault ID do not match")
+		}
+		return nil
+	}
+}
+
+func testAccBackupVaultConfig(backupRegion string) string {
+	return fmt.Sprintf(`
+	resource "netapp-gcp_backup_vault" "terraform-acceptance-test-1" {
+		provider = netapp-gcp
+		region = "us-east4"
+		backup_region = "%s"
+	}
+	`, backupRegion)
+}
+
+func TestGCPBackupVault_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	defer func(interval time.Duration) { jobPollMinInterval = interval }(jobPollMinInterval)
+	jobPollMinInterval = time.Millisecond
+	srv.Put("us-east4/Volumes/vol-1", map[string]interface{}{"name": "main-volume", "creationToken": "main-volume", "lifeCycleStateDetails": "Available for use"})
+	client := testFakeClient(srv)
+	r := resourceGCPBackupVault()
+	config := map[string]interface{}{"region": "us-east4", "backup_region": "us-west2"}
+
+	vault := testFakeApply(t, r, nil, config, client)
+	if vault.ID == "" || vault.Attributes["backup_region"] != "us-west2" || vault.Attributes["lifecycle_state"] != "available" {
+		t.Fatalf("unexpected state after create %v", vault)
+	}
+
+	// new backups of volumes in us-east4 are stored in us-west2
+	backup := testFakeApply(t, resourceGCPVolumeBackup(), nil, map[string]interface{}{"name": "nightly", "region": "us-east4", "volume_id": "vol-1"}, client)
+	if backup.Attributes["backup_region"] != "us-west2" {
+		t.Fatalf("unexpected backup state %v", backup)
+	}
+
+	// and can be restored from us-west2
+	restored := testFakeApply(t, resourceGCPVolume(), nil, map[string]interface{}{"name": "restored", "region": "us-west2", "protocol_types": []interface{}{"NFSv3"},
+		"network": "default", "size": 1024, "backup_id": backup.ID}, client)
+	if volume := srv.Get("us-west2/Volumes/" + restored.ID); volume == nil || volume["backupId"] != backup.ID {
+		t.Fatalf("unexpected restored volume %v", volume)
+	}
+
+	config["backup_region"] = "europe-west1"
+	id := vault.ID
+	vault = testFakeApply(t, r, vault, config, client)
+	if vault.ID != id || vault.Attributes["backup_region"] != "europe-west1" {
+		t.Fatalf("unexpected state after update %v", vault)
+	}
+	if v := srv.Get("us-east4/Storage/BackupVault/" + id); v["backupRegion"] != "europe-west1" {
+		t.Fatalf("backup vault was not updated %v", v)
+	}
+
+	if vault = testFakeApply(t, r, vault, nil, client); vault != nil {
+		t.Fatalf("unexpected state after destroy %v", vault)
+	}
+	if v := srv.Get("us-east4/Storage/BackupVault/" + id); v != nil {
+		t.Fatalf("backup vault was not deleted %v", v)
+	}
+}
+
+func TestGCPBackupVaultImport_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	srv.Put("europe-west1/Storage/BackupVault/vault-1", map[string]interface{}{"backupRegion": "europe-west4"})
+	client := testFakeClient(srv)
+	client.Region = "us-east4"
+	r := resourceGCPBackupVault()
+
+	d := r.Data(&terraform.InstanceState{ID: "europe-west1:vault-1"})
+	imported, err := r.Importer.StateContext(context.Background(), d, client)
+	if err != nil {
+		t.Fatalf("import failed: %s", err)
+	}
+	if diags := r.ReadContext(context.Background(), imported[0], client); diags.HasError() {
+		t.Fatalf("read after import failed: %v", diags)
+	}
+	state := imported[0].State()
+	if state.ID != "vault-1" || state.Attributes["region"] != "europe-west1" || state.Attributes["backup_region"] != "europe-west4" {
+		t.Fatalf("unexpected state after import %v", state)
+	}
+
+	for _, id := range []string{"vault-1", "europe-west1:", ":vault-1"} {
+		if _, err := r.Importer.StateContext(context.Background(), r.Data(&terraform.InstanceState{ID: id}), client); err == nil {
+			t.Errorf("expected the import of %s to fail", id)
+		}
+	}
+}
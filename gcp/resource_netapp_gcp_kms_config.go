@@ -1,20 +1,22 @@
 package gcp
 
 import (
-	"fmt"
+	"context"
 	"log"
 
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/restapi"
 )
 
 func resourceGCPKMSConfig() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceGCPKMSConfigCreate,
-		Read:   resourceGCPKMSConfigRead,
-		Delete: resourceGCPKMSConfigDelete,
-		Update: resourceGCPKMSConfigUpdate,
+		CreateContext: resourceGCPKMSConfigCreate,
+		ReadContext:   resourceGCPKMSConfigRead,
+		DeleteContext: resourceGCPKMSConfigDelete,
+		UpdateContext: resourceGCPKMSConfigUpdate,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: map[string]*schema.Schema{
 			//these available fields are required for create and update.
@@ -40,13 +42,14 @@ func resourceGCPKMSConfig() *schema.Resource {
 			},
 			"region": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 			},
 		},
 	}
 }
 
-func resourceGCPKMSConfigCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPKMSConfigCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client)
 
 	kms := kmsConfig{}
@@ -54,84 +57,101 @@ func resourceGCPKMSConfigCreate(d *schema.ResourceData, meta interface{}) error
 	kms.KeyName = d.Get("key_name").(string)
 	kms.KeyRingLocation = d.Get("key_ring_location").(string)
 	kms.Network = d.Get("network").(string)
-	kms.Region = d.Get("region").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	kms.Region = region
 
 	if v, ok := d.GetOk("key_project_id"); ok {
 		kms.KeyProjectID = v.(string)
 	}
 
-	res, err := client.createKMSConfig(&kms)
+	res, err := client.createKMSConfig(ctx, &kms)
 	if err != nil {
 		log.Print("Error creating kms config")
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(res.ID)
 
 	log.Printf("Created KMS in region: %v", kms.Region)
 
-	return resourceGCPKMSConfigRead(d, meta)
+	return resourceGCPKMSConfigRead(ctx, d, meta)
 }
 
-func resourceGCPKMSConfigRead(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPKMSConfigRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client)
 	id := d.Id()
 	kmsConfig := kmsConfig{}
-	kmsConfig.Region = d.Get("region").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	kmsConfig.Region = region
 	kmsConfig.ID = d.Id()
-	res, err := client.getKMSConfig(&kmsConfig)
+	res, err := client.getKMSConfig(ctx, &kmsConfig)
 	if err != nil {
-		return err
+		if restapi.IsNotFound(err) {
+			log.Printf("KMS config %s not found, removing it from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 	if res.ID != id {
-		return fmt.Errorf("Expected kms with id: %v, Response contained kms with id: %v",
+		return diag.Errorf("Expected kms with id: %v, Response contained kms with id: %v",
 			d.Id(), res.ID)
 	}
 
 	if err := d.Set("key_name", res.KeyName); err != nil {
-		return fmt.Errorf("Error reading key name: %s", err)
+		return diag.Errorf("Error reading key name: %s", err)
 	}
 
 	if err := d.Set("key_ring_name", res.KeyRing); err != nil {
-		return fmt.Errorf("Error reading key ring name: %s", err)
+		return diag.Errorf("Error reading key ring name: %s", err)
 	}
 
 	if err := d.Set("key_ring_location", res.KeyRingLocation); err != nil {
-		return fmt.Errorf("Error reading key ring location: %s", err)
+		return diag.Errorf("Error reading key ring location: %s", err)
 	}
 
 	if _, ok := d.GetOk("key_project_id"); ok {
 		if err := d.Set("key_project_id", res.KeyProjectID); err != nil {
-			return fmt.Errorf("Error reading key project id: %s", err)
+			return diag.Errorf("Error reading key project id: %s", err)
 		}
 	}
 
 	if err := d.Set("network", res.Network); err != nil {
-		return fmt.Errorf("Error reading network id: %s", err)
+		return diag.Errorf("Error reading network id: %s", err)
 	}
 
 	if err := d.Set("region", res.Region); err != nil {
-		return fmt.Errorf("Error reading region: %s", err)
+		return diag.Errorf("Error reading KMS config region: %s", err)
 	}
 
 	return nil
 }
 
-func resourceGCPKMSConfigDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPKMSConfigDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Deleting kms: %#v", d)
 	client := meta.(*Client)
 	kms := kmsConfig{}
-	kms.Region = d.Get("region").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	kms.Region = region
 	kms.ID = d.Id()
-	_, deleteErr := client.deleteKMSConfig(&kms)
-	if deleteErr != nil {
-		return deleteErr
+	_, deleteErr := client.deleteKMSConfig(ctx, &kms)
+	if deleteErr != nil && !restapi.IsNotFound(deleteErr) {
+		return diag.FromErr(deleteErr)
 	}
 	d.SetId("")
 
 	return nil
 }
 
-func resourceGCPKMSConfigUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPKMSConfigUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("updating kms: %#v", d)
 	client := meta.(*Client)
 	kms := kmsConfig{}
@@ -140,16 +160,20 @@ func resourceGCPKMSConfigUpdate(d *schema.ResourceData, meta interface{}) error
 	kms.KeyRing = d.Get("key_ring_name").(string)
 	kms.KeyRingLocation = d.Get("key_ring_location").(string)
 	kms.Network = d.Get("network").(string)
-	kms.Region = d.Get("region").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	kms.Region = region
 	kms.ID = d.Id()
 
 	if v, ok := d.GetOk("key_project_id"); ok {
 		kms.KeyProjectID = v.(string)
 	}
 
-	_, err := client.updateKMSConfig(&kms)
+	_, err = client.updateKMSConfig(ctx, &kms)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	return resourceGCPKMSConfigRead(d, meta)
+	return resourceGCPKMSConfigRead(ctx, d, meta)
 }
@@ -1,23 +1,28 @@
 package gcp
 
 import (
-	"fmt"
+	"context"
 	"log"
 	"time"
 
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/restapi"
 )
 
 func resourceGCPSnapshot() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceGCPSnapshotCreate,
-		Read:   resourceGCPSnapshotRead,
-		Delete: resourceGCPSnapshotDelete,
-		Exists: resourceGCPSnapshotExists,
-		Update: resourceGCPSnapshotUpdate,
+		CreateContext: resourceGCPSnapshotCreate,
+		ReadContext:   resourceGCPSnapshotRead,
+		DeleteContext: resourceGCPSnapshotDelete,
+		UpdateContext: resourceGCPSnapshotUpdate,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: importVolumeItemState("<region>:<volume_id>:<snapshot_id>"),
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -27,24 +32,53 @@ func resourceGCPSnapshot() *schema.Resource {
 			},
 			"region": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 				ForceNew: true,
 			},
 			"volume_name": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				AtLeastOneOf: []string{"volume_id", "volume_name", "creation_token"},
 			},
 			"creation_token": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				AtLeastOneOf: []string{"volume_id", "volume_name", "creation_token"},
+			},
+			"volume_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				AtLeastOneOf:  []string{"volume_id", "volume_name", "creation_token"},
+				ConflictsWith: []string{"volume_name", "creation_token"},
+			},
+			"created": {
 				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Computed: true,
+			},
+			"used_bytes": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"lifecycle_state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"lifecycle_state_details": {
+				Type:     schema.TypeString,
+				Computed: true,
 			},
 		},
 	}
 }
 
-func resourceGCPSnapshotCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPSnapshotCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Creating snapshot: %#v", d)
 
 	client := meta.(*Client)
@@ -52,163 +86,149 @@ func resourceGCPSnapshotCreate(d *schema.ResourceData, meta interface{}) error {
 	snapshot := createSnapshotRequest{}
 
 	snapshot.Name = d.Get("name").(string)
-	snapshot.Region = d.Get("region").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	snapshot.Region = region
 
 	volume := volumeRequest{}
 	volume.Region = snapshot.Region
 
 	volume.Name = d.Get("volume_name").(string)
 	volume.CreationToken = d.Get("creation_token").(string)
+	volume.VolumeID = d.Get("volume_id").(string)
 
 	// Check the volume status. Start creating snapshot when volume is ready to use
-	retries := 0
-	for {
-		volresult, err := client.getVolumeByNameOrCreationToken(volume)
-		if err != nil {
-			log.Print("Error getting volume ID")
-			return err
-		}
-		if volresult.LifeCycleStateDetails != "Available for use" {
-			if retries < 3 {
-				log.Printf("Volume %s is not ready. Wait for 5 seconds and check again.\n", volume.Name)
-				time.Sleep(5 * time.Second)
-				retries++
-			} else {
-				log.Printf("Volume %s is not ready.\n", volume.Name)
-				return err
-			}
-		} else {
-			snapshot.VolumeID = volresult.VolumeID
-			break
-		}
+	volresult, err := client.waitForVolumeAvailable(ctx, volume, d.Timeout(schema.TimeoutCreate), 5*time.Second)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	snapshot.VolumeID = volresult.VolumeID
+	if err := setVolumeItemVolume(d, volresult); err != nil {
+		return diag.FromErr(err)
 	}
 
-	res, err := client.createSnapshot(&snapshot)
+	res, err := client.createSnapshot(ctx, &snapshot)
 	if err != nil {
 		log.Print("Error creating snapshot")
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(res.Name.JobID.SnapshotID)
 	log.Printf("Created snapshot: %v", snapshot.Name)
 
-	return resourceGCPSnapshotRead(d, meta)
+	return resourceGCPSnapshotRead(ctx, d, meta)
 }
 
-func resourceGCPSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPSnapshotRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Reading snapshot: %#v", d)
 	client := meta.(*Client)
 
 	snapshot := listSnapshotRequest{}
 
-	snapshot.Region = d.Get("region").(string)
-
-	volume := volumeRequest{}
-	volume.Region = snapshot.Region
-	volume.Name = d.Get("volume_name").(string)
-	volume.CreationToken = d.Get("creation_token").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	snapshot.Region = region
+	if err := d.Set("region", region); err != nil {
+		return diag.Errorf("Error reading snapshot region: %s", err)
+	}
 
-	volresult, err := client.getVolumeByNameOrCreationToken(volume)
+	volumeID, err := getVolumeItemVolumeID(ctx, d, client, snapshot.Region)
 	if err != nil {
 		log.Print("Error getting volume ID")
-		return err
+		return diag.FromErr(err)
 	}
-
-	snapshot.VolumeID = volresult.VolumeID
+	snapshot.VolumeID = volumeID
 
 	id := d.Id()
 	snapshot.SnapshotID = id
 	var res listSnapshotResult
-	res, err = client.getSnapshotByID(snapshot)
+	res, err = client.getSnapshotByID(ctx, snapshot)
 	if err != nil {
+		if restapi.IsNotFound(err) {
+			log.Printf("Snapshot %s not found, removing it from state", id)
+			d.SetId("")
+			return nil
+		}
 		log.Print("Error getting Snapshot")
-		return err
+		return diag.FromErr(err)
 	}
 
+	if res.SnapshotID == "" {
+		log.Printf("Snapshot %s is deleted, removing it from state", id)
+		d.SetId("")
+		return nil
+	}
 	if res.SnapshotID != id {
-		return fmt.Errorf("Expected Snapshot ID %v, Response contained Snapshot ID %v", id, res.SnapshotID)
+		return diag.Errorf("Expected Snapshot ID %v, Response contained Snapshot ID %v", id, res.SnapshotID)
+	}
+
+	// volume_name and creation_token are read from the volume when they are missing in state, like for
+	// snapshots created by volume_id. They are not refreshed otherwise, so renaming the volume does not
+	// replace the snapshot.
+	if d.Get("volume_name").(string) == "" || d.Get("creation_token").(string) == "" {
+		volume, err := client.getVolumeByID(ctx, volumeRequest{Region: snapshot.Region, VolumeID: volumeID})
+		if err != nil {
+			return diag.Errorf("Error reading snapshot volume %s: %s", volumeID, err)
+		}
+		if err := setVolumeItemVolume(d, volume); err != nil {
+			return diag.Errorf("Error reading snapshot volume: %s", err)
+		}
+	} else if err := d.Set("volume_id", volumeID); err != nil {
+		return diag.Errorf("Error reading snapshot volume_id: %s", err)
+	}
+
+	attributes := map[string]interface{}{
+		"name":                    res.Name,
+		"created":                 res.Created,
+		"used_bytes":              res.UsedBytes,
+		"lifecycle_state":         res.LifeCycleState,
+		"lifecycle_state_details": res.LifeCycleStateDetails,
+	}
+	for k, v := range attributes {
+		if err := d.Set(k, v); err != nil {
+			return diag.Errorf("Error reading snapshot %s: %s", k, err)
+		}
 	}
 
 	return nil
 }
 
-func resourceGCPSnapshotDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPSnapshotDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Deleting snapshot: %#v", d)
 
 	client := meta.(*Client)
 
 	snapshot := deleteSnapshotRequest{}
 
-	snapshot.Region = d.Get("region").(string)
-
-	volume := volumeRequest{}
-	volume.Region = snapshot.Region
-	volume.Name = d.Get("volume_name").(string)
-	volume.CreationToken = d.Get("creation_token").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	snapshot.Region = region
 
-	volresult, err := client.getVolumeByNameOrCreationToken(volume)
+	volumeID, err := getVolumeItemVolumeID(ctx, d, client, snapshot.Region)
 	if err != nil {
 		log.Print("Error getting volume ID")
-		return err
+		return diag.FromErr(err)
 	}
-
-	snapshot.VolumeID = volresult.VolumeID
+	snapshot.VolumeID = volumeID
 
 	id := d.Id()
 	snapshot.SnapshotID = id
 
-	deleteErr := client.deleteSnapshot(snapshot)
-	if deleteErr != nil {
-		return deleteErr
+	deleteErr := client.deleteSnapshot(ctx, snapshot)
+	if deleteErr != nil && !restapi.IsNotFound(deleteErr) {
+		return diag.FromErr(deleteErr)
 	}
 
 	return nil
 }
 
-func resourceGCPSnapshotExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	log.Printf("Checking existence of snapshot: %#v", d)
-	client := meta.(*Client)
-
-	snapshot := listSnapshotRequest{}
-
-	id := d.Id()
-	snapshot.SnapshotID = id
-	snapshot.Region = d.Get("region").(string)
-
-	volume := volumeRequest{}
-	volume.Region = snapshot.Region
-	volume.Name = d.Get("volume_name").(string)
-	volume.CreationToken = d.Get("creation_token").(string)
-
-	volresult, err := client.getVolumeByNameOrCreationToken(volume)
-	if err != nil {
-		log.Print("Error getting volume ID")
-		return false, err
-	}
-
-	snapshot.VolumeID = volresult.VolumeID
-
-	var res listSnapshotResult
-	res, err = client.getSnapshotByID(snapshot)
-	if err != nil {
-		if err, ok := err.(*restapi.ResponseError); ok {
-			if err.Name == "xUnknown" {
-				d.SetId("")
-				return false, nil
-			}
-		}
-		return false, err
-	}
-
-	if res.SnapshotID != id {
-		d.SetId("")
-		return false, nil
-	}
-
-	return true, nil
-}
-
-func resourceGCPSnapshotUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPSnapshotUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Updating snapshot: %#v", d)
 
 	client := meta.(*Client)
@@ -217,27 +237,25 @@ func resourceGCPSnapshotUpdate(d *schema.ResourceData, meta interface{}) error {
 	id := d.Id()
 	snapshot.SnapshotID = id
 	snapshot.Name = d.Get("name").(string)
-	snapshot.Region = d.Get("region").(string)
-
-	volume := volumeRequest{}
-	volume.Region = snapshot.Region
-	volume.Name = d.Get("volume_name").(string)
-	volume.CreationToken = d.Get("creation_token").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	snapshot.Region = region
 
-	volresult, err := client.getVolumeByNameOrCreationToken(volume)
+	volumeID, err := getVolumeItemVolumeID(ctx, d, client, snapshot.Region)
 	if err != nil {
 		log.Print("Error getting volume ID")
-		return err
+		return diag.FromErr(err)
 	}
+	snapshot.VolumeID = volumeID
 
-	snapshot.VolumeID = volresult.VolumeID
-
-	err = client.updateSnapshot(snapshot)
+	err = client.updateSnapshot(ctx, snapshot)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("Updated snapshot: %v", snapshot.Name)
 
-	return resourceGCPSnapshotRead(d, meta)
+	return resourceGCPSnapshotRead(ctx, d, meta)
 }
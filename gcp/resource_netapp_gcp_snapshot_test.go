@@ -1,21 +1,23 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/cvstest"
 )
 
 func TestAccGCPSnapshot_basic(t *testing.T) {
 
 	var snapshot listSnapshotResult
 	resource.Test(t, resource.TestCase{
-		PreCheck:     func() { testAccPreCheck(t) },
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckSnapshotDestroy,
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckSnapshotDestroy,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSnapshotConfigCreate(VolName, Region, SnapshotName),
@@ -39,6 +41,57 @@ func TestAccGCPSnapshot_basic(t *testing.T) {
 	})
 }
 
+func TestGCPSnapshot_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	defer func(interval time.Duration) { jobPollMinInterval = interval }(jobPollMinInterval)
+	jobPollMinInterval = time.Millisecond
+	srv.Put("us-east4/Volumes/vol-1", map[string]interface{}{"name": VolName, "creationToken": VolName, "lifeCycleStateDetails": "Available for use"})
+
+	client := testFakeClient(srv)
+	client.Region = Region
+	r := resourceGCPSnapshot()
+
+	state := testFakeApply(t, r, nil, map[string]interface{}{"name": SnapshotName, "volume_name": VolName}, client)
+	if state.ID == "" || state.Attributes["name"] != SnapshotName || state.Attributes["region"] != Region {
+		t.Fatalf("unexpected state after create %v", state)
+	}
+	if snapshot := srv.Get("us-east4/Volumes/vol-1/Snapshots/" + state.ID); snapshot["name"] != SnapshotName {
+		t.Fatalf("unexpected snapshot %v", snapshot)
+	}
+	if state.Attributes["volume_id"] != "vol-1" || state.Attributes["creation_token"] != VolName || state.Attributes["lifecycle_state"] != "available" {
+		t.Fatalf("unexpected state after create %v", state)
+	}
+
+	// a snapshot renamed outside of terraform is detected as drift
+	renamed := srv.Get("us-east4/Volumes/vol-1/Snapshots/" + state.ID)
+	renamed["name"] = "renamed"
+	renamed["created"] = "2026-10-18T08:00:00.000Z"
+	renamed["usedBytes"] = 4096
+	srv.Put("us-east4/Volumes/vol-1/Snapshots/"+state.ID, renamed)
+	d := r.Data(state)
+	if diags := resourceGCPSnapshotRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if d.Get("name") != "renamed" || d.Get("created") != "2026-10-18T08:00:00.000Z" || d.Get("used_bytes") != 4096 {
+		t.Fatalf("unexpected state after read %v", d.State())
+	}
+	state = d.State()
+
+	state = testFakeApply(t, r, state, map[string]interface{}{"name": "update-test-snapshot", "region": Region, "volume_name": VolName}, client)
+	if state.Attributes["name"] != "update-test-snapshot" {
+		t.Fatalf("unexpected state after update %v", state)
+	}
+
+	id := state.ID
+	if state = testFakeApply(t, r, state, nil, client); state != nil {
+		t.Fatalf("unexpected state after destroy %v", state)
+	}
+	if snapshot := srv.Get("us-east4/Volumes/vol-1/Snapshots/" + id); snapshot != nil {
+		t.Fatalf("snapshot was not deleted %v", snapshot)
+	}
+}
+
 func testAccCheckSnapshotDestroy(state *terraform.State) error {
 
 	client := testAccProvider.Meta().(*Client)
@@ -53,18 +106,18 @@ func testAccCheckSnapshotDestroy(state *terraform.State) error {
 		volume.Name = rs.Primary.Attributes["volume_name"]
 		volume.CreationToken = rs.Primary.Attributes["creation_token"]
 
-		volresult, err := client.getVolumeByNameOrCreationToken(volume)
+		volresult, err := client.getVolumeByNameOrCreationToken(context.Background(), volume)
 		if err == nil {
 			retriveSnapshot := listSnapshotRequest{}
 			retriveSnapshot.Region = volume.Region
 			retriveSnapshot.VolumeID = volresult.VolumeID
 			retriveSnapshot.SnapshotID = rs.Primary.ID
 
-			response, err := client.getSnapshotByID(retriveSnapshot)
+			response, err := client.getSnapshotByID(context.Background(), retriveSnapshot)
 
 			if err == nil {
 				if response.SnapshotID != "" {
-					return fmt.Errorf("Error snapshot %s still exists in %s", rs.Primary.ID, response)
+					return fmt.Errorf("Error snapshot %s still exists in %s", rs.Primary.ID, volresult.Name)
 				}
 			}
 		}
@@ -94,7 +147,7 @@ func testAccCheckSnapshotExists(name string, snapshot *listSnapshotResult) resou
 		volume.Name = rs.Primary.Attributes["volume_name"]
 		volume.CreationToken = rs.Primary.Attributes["creation_token"]
 
-		volresult, err := client.getVolumeByNameOrCreationToken(volume)
+		volresult, err := client.getVolumeByNameOrCreationToken(context.Background(), volume)
 		if err != nil {
 			return fmt.Errorf("Error getting volume ID")
 		}
@@ -105,7 +158,7 @@ func testAccCheckSnapshotExists(name string, snapshot *listSnapshotResult) resou
 		retriveSnapshot.VolumeID = volresult.VolumeID
 
 		var res listSnapshotResult
-		res, err = client.getSnapshotByID(retriveSnapshot)
+		res, err = client.getSnapshotByID(context.Background(), retriveSnapshot)
 		if err != nil {
 			return fmt.Errorf("Not able to get snapshot")
 		}
@@ -175,3 +228,98 @@ func testAccSnapshotConfigUpdate(Volume string, Location string, Snapshot string
 	}
 	`, Volume, Location, Snapshot)
 }
+
+func TestGCPSnapshotImport_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	srv.Put("europe-west1/Volumes/vol-1", map[string]interface{}{"name": VolName, "creationToken": "vol-token-1"})
+	srv.Put("europe-west1/Volumes/vol-1/Snapshots/snap-1", map[string]interface{}{"name": SnapshotName, "volumeId": "vol-1"})
+	client := testFakeClient(srv)
+	client.Region = "us-east4"
+	r := resourceGCPSnapshot()
+
+	d := r.Data(&terraform.InstanceState{ID: "europe-west1:vol-1:snap-1"})
+	imported, err := r.Importer.StateContext(context.Background(), d, client)
+	if err != nil {
+		t.Fatalf("import failed: %s", err)
+	}
+	if diags := r.ReadContext(context.Background(), imported[0], client); diags.HasError() {
+		t.Fatalf("read after import failed: %v", diags)
+	}
+	state := imported[0].State()
+	if state.ID != "snap-1" || state.Attributes["region"] != "europe-west1" || state.Attributes["name"] != SnapshotName ||
+		state.Attributes["volume_name"] != VolName || state.Attributes["creation_token"] != "vol-token-1" || state.Attributes["volume_id"] != "vol-1" {
+		t.Fatalf("unexpected state after import %v", state)
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{"name": SnapshotName, "region": "europe-west1", "volume_name": VolName})
+	if diff, err := r.Diff(context.Background(), state, config, client); err != nil || !diff.Empty() {
+		t.Errorf("unexpected plan after import %v: %v", diff, err)
+	}
+
+	for _, id := range []string{"snap-1", "vol-1:snap-1", "europe-west1:unknown:snap-1"} {
+		if _, err := r.Importer.StateContext(context.Background(), r.Data(&terraform.InstanceState{ID: id}), client); err == nil {
+			t.Errorf("expected the import of %s to fail", id)
+		}
+	}
+}
+
+func TestGCPSnapshotByVolumeID_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	defer func(interval time.Duration) { jobPollMinInterval = interval }(jobPollMinInterval)
+	jobPollMinInterval = time.Millisecond
+	srv.Put("us-east4/Volumes/vol-1", map[string]interface{}{"name": VolName, "creationToken": VolName, "lifeCycleStateDetails": "Available for use"})
+
+	client := testFakeClient(srv)
+	client.Region = Region
+	r := resourceGCPSnapshot()
+
+	if diags := r.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{"name": SnapshotName, "volume_id": "vol-1", "volume_name": VolName})); !diags.HasError() {
+		t.Errorf("expected volume_id to conflict with volume_name")
+	}
+	if diags := r.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{"name": SnapshotName})); !diags.HasError() {
+		t.Errorf("expected an error without volume")
+	}
+
+	state := testFakeApply(t, r, nil, map[string]interface{}{"name": SnapshotName, "volume_id": "vol-1"}, client)
+	if state.Attributes["volume_id"] != "vol-1" || state.Attributes["volume_name"] != VolName || state.Attributes["creation_token"] != VolName {
+		t.Fatalf("unexpected state after create %v", state)
+	}
+
+	// renaming the volume does not affect the snapshot
+	volume := srv.Get("us-east4/Volumes/vol-1")
+	volume["name"] = "renamed-volume"
+	srv.Put("us-east4/Volumes/vol-1", volume)
+
+	state = testFakeApply(t, r, state, map[string]interface{}{"name": "update-test-snapshot", "volume_id": "vol-1"}, client)
+	if state.Attributes["name"] != "update-test-snapshot" {
+		t.Fatalf("unexpected state after update %v", state)
+	}
+	if state = testFakeApply(t, r, state, nil, client); state != nil {
+		t.Fatalf("unexpected state after destroy %v", state)
+	}
+
+	for _, request := range srv.Requests() {
+		if request == "GET us-east4/Volumes" || request == "GET -/Volumes" {
+			t.Errorf("unexpected volume list request %s", request)
+		}
+	}
+}
+
+func TestGCPSnapshotReadVolume_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	srv.Put("us-east4/Volumes/vol-1", map[string]interface{}{"name": VolName, "creationToken": "vol-token-1"})
+	srv.Put("us-east4/Volumes/vol-1/Snapshots/snap-1", map[string]interface{}{"name": SnapshotName, "volumeId": "vol-1"})
+	client := testFakeClient(srv)
+	r := resourceGCPSnapshot()
+
+	d := r.Data(&terraform.InstanceState{ID: "snap-1", Attributes: map[string]string{"region": "us-east4", "volume_id": "vol-1"}})
+	if diags := r.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if d.Get("volume_name") != VolName || d.Get("creation_token") != "vol-token-1" || d.Get("volume_id") != "vol-1" {
+		t.Fatalf("unexpected state after read %v", d.State())
+	}
+}
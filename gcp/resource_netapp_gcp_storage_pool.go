@@ -1,23 +1,32 @@
 package gcp
 
 import (
-	"fmt"
+	"context"
 	"log"
 	"regexp"
 	"strings"
+	"time"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/restapi"
 )
 
 func resourceGCPStoragePool() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceGCPStoragePoolCreate,
-		Read:   resourceGCPStoragePoolRead,
-		Delete: resourceGCPStoragePoolDelete,
-		Update: resourceGCPStoragePoolUpdate,
+		CreateContext: resourceGCPStoragePoolCreate,
+		ReadContext:   resourceGCPStoragePoolRead,
+		DeleteContext: resourceGCPStoragePoolDelete,
+		UpdateContext: resourceGCPStoragePoolUpdate,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: customizeDiffEffectiveBillingLabels,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -30,7 +39,8 @@ func resourceGCPStoragePool() *schema.Resource {
 			},
 			"region": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 			},
 			"service_level": {
 				Type:         schema.TypeString,
@@ -89,16 +99,25 @@ func resourceGCPStoragePool() *schema.Resource {
 					},
 				},
 			},
+			"effective_billing_labels": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
 		},
 	}
 }
 
-func resourceGCPStoragePoolCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPStoragePoolCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Creating storage pool: %#v", d.Get("name").(string))
 	client := meta.(*Client)
 	pool := storagePool{}
 	// required attributes
-	pool.Region = d.Get("region").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	pool.Region = region
 	pool.Name = d.Get("name").(string)
 	pool.ServiceLevel = d.Get("service_level").(string)
 	pool.SizeInBytes = d.Get("size").(int) * GiBToBytes
@@ -119,68 +138,72 @@ func resourceGCPStoragePoolCreate(d *schema.ResourceData, meta interface{}) erro
 	if v, ok := d.GetOk("secondary_zone"); ok {
 		pool.SecondaryZone = v.(string)
 	}
-	if v, ok := d.GetOk("billing_label"); ok {
-		values := v.(*schema.Set)
-		if values.Len() > 0 {
-			labels := make([]billingLabel, 0, values.Len())
-			labels = append(labels, expandBillingLabel(values)...)
-			pool.BillingLabels = labels
-		}
+	if labels := expandBillingLabel(d.Get("billing_label").(*schema.Set), client.DefaultBillingLabels); len(labels) > 0 {
+		pool.BillingLabels = labels
 	}
 
 	if v, ok := d.GetOk("shared_vpc_project_number"); ok {
 		pool.SharedVpcProjectNumber = v.(string)
 	}
 
-	res, err := client.createStoragePool(&pool)
+	res, err := client.createStoragePool(ctx, &pool)
 	if err != nil {
 		log.Printf("Error creating storage pool: %#v", err)
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(res.PoolID)
 
 	log.Printf("Created storage pool in region: %v", res.Region)
 
-	return resourceGCPStoragePoolRead(d, meta)
+	return resourceGCPStoragePoolRead(ctx, d, meta)
 }
 
-func resourceGCPStoragePoolRead(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPStoragePoolRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client)
 	id := d.Id()
 	pool := storagePool{}
+	// without region, like on terraform import, the region is looked up by the ID
 	pool.Region = d.Get("region").(string)
 	pool.PoolID = id
 	var res storagePool
-	res, err := client.getStoragePoolByID(&pool)
+	res, err := client.getStoragePoolByID(ctx, &pool)
 	if err != nil {
-		return err
+		if restapi.IsNotFound(err) {
+			log.Printf("Storage pool %s not found, removing it from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 	if strings.ToLower(res.State) == "deleted" {
 		d.SetId("")
 		return nil
 	}
 	if res.PoolID != id {
-		return fmt.Errorf("expected storage pool with id: %v, Response contained storage pool with id: %v",
+		return diag.Errorf("expected storage pool with id: %v, Response contained storage pool with id: %v",
 			d.Id(), res.PoolID)
 	}
 	if err := d.Set("size", res.SizeInBytes/GiBToBytes); err != nil {
-		return fmt.Errorf("error reading storage pool size: %s", err)
+		return diag.Errorf("error reading storage pool size: %s", err)
 	}
 
 	if err := d.Set("region", res.Region); err != nil {
-		return fmt.Errorf("error reading storage pool region: %s", err)
+		return diag.Errorf("error reading storage pool region: %s", err)
 	}
 
 	if err := d.Set("name", res.Name); err != nil {
-		return fmt.Errorf("error reading storage pool name: %s", err)
+		return diag.Errorf("error reading storage pool name: %s", err)
 	}
 
 	if _, ok := d.GetOk("billing_label"); ok {
-		labels := flattenBillingLabel(res.BillingLabels)
+		labels := flattenBillingLabel(removeDefaultBillingLabels(res.BillingLabels, client.DefaultBillingLabels, d.Get("billing_label").(*schema.Set)))
 		if err := d.Set("billing_label", labels); err != nil {
-			return fmt.Errorf("error reading storage pool billing_label: %s", err)
+			return diag.Errorf("error reading storage pool billing_label: %s", err)
 		}
 	}
+	if err := d.Set("effective_billing_labels", flattenEffectiveBillingLabels(res.BillingLabels)); err != nil {
+		return diag.Errorf("error reading storage pool effective_billing_labels: %s", err)
+	}
 	// res.Network either contains simple network name or
 	// projects/${HOST_PROJECT_ID}/global/networks/${SHARED_VPC_NAME}, usually (but not exclusively) for shared VPC
 	nws := strings.Split(res.Network, "/")
@@ -194,34 +217,34 @@ func resourceGCPStoragePoolRead(d *schema.ResourceData, meta interface{}) error
 		// if network path contains different projectId than our project, it is shared-VPC
 		if nws[1] != client.Project {
 			if err := d.Set("shared_vpc_project_number", nws[1]); err != nil {
-				return fmt.Errorf("error reading shared_vpc_project_number: %s", err)
+				return diag.Errorf("error reading shared_vpc_project_number: %s", err)
 			}
 		}
 	} else {
-		return fmt.Errorf("network path %s is invalid", res.Network)
+		return diag.Errorf("network path %s is invalid", res.Network)
 	}
 	if err := d.Set("network", network); err != nil {
-		return fmt.Errorf("error reading volume network: %s", err)
+		return diag.Errorf("error reading volume network: %s", err)
 	}
 
 	if err := d.Set("global_ad_access", res.GlobalILB); err != nil {
-		return fmt.Errorf("error reading storage pool global_ad_access flag: %s", err)
+		return diag.Errorf("error reading storage pool global_ad_access flag: %s", err)
 	}
 
 	if err := d.Set("managed_pool", res.ManagedPool); err != nil {
-		return fmt.Errorf("error reading storage pool managed_pool flag: %s", err)
+		return diag.Errorf("error reading storage pool managed_pool flag: %s", err)
 	}
 
 	if err := d.Set("zone", res.Zone); err != nil {
-		return fmt.Errorf("error reading storage pool zone: %s", err)
+		return diag.Errorf("error reading storage pool zone: %s", err)
 	}
 
 	if err := d.Set("secondary_zone", res.SecondaryZone); err != nil {
-		return fmt.Errorf("error reading storage pool secondary_zone: %s", err)
+		return diag.Errorf("error reading storage pool secondary_zone: %s", err)
 	}
 
 	if err := d.Set("service_level", res.ServiceLevel); err != nil {
-		return fmt.Errorf("error reading storage pool service_level: %s", err)
+		return diag.Errorf("error reading storage pool service_level: %s", err)
 	}
 	// RegionalHA is old parameter used in legacy volumes (managed_pool)
 	// It should not be used anymore and is replaced by
@@ -233,36 +256,44 @@ func resourceGCPStoragePoolRead(d *schema.ResourceData, meta interface{}) error
 		res.RegionalHA = true
 	}
 	// if err := d.Set("regional_ha", res.RegionalHA); err != nil {
-	// 	return fmt.Errorf("error setting storage pool regional_ha: %s", err)
+	// 	return diag.Errorf("error setting storage pool regional_ha: %s", err)
 	// }
 
 	if err := d.Set("storage_class", res.StorageClass); err != nil {
-		return fmt.Errorf("error reading storage pool storage_class: %s", err)
+		return diag.Errorf("error reading storage pool storage_class: %s", err)
 	}
 
 	return nil
 }
 
-func resourceGCPStoragePoolDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPStoragePoolDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Deleting storage pool: %#v", d.Get("name"))
 	client := meta.(*Client)
 	pool := storagePool{}
-	pool.Region = d.Get("region").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	pool.Region = region
 	pool.PoolID = d.Id()
-	deleteErr := client.deleteStoragePool(&pool)
-	if deleteErr != nil {
-		return deleteErr
+	deleteErr := client.deleteStoragePool(ctx, &pool)
+	if deleteErr != nil && !restapi.IsNotFound(deleteErr) {
+		return diag.FromErr(deleteErr)
 	}
 	d.SetId("")
 
 	return nil
 }
 
-func resourceGCPStoragePoolUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPStoragePoolUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client)
 	pool := storagePool{}
 	// all of the following are required for API: update.
-	pool.Region = d.Get("region").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	pool.Region = region
 	pool.Name = d.Get("name").(string)
 	pool.PoolID = d.Id()
 	pool.ServiceLevel = d.Get("service_level").(string)
@@ -271,10 +302,8 @@ func resourceGCPStoragePoolUpdate(d *schema.ResourceData, meta interface{}) erro
 		pool.SizeInBytes = d.Get("size").(int) * GiBToBytes
 	}
 
-	if d.HasChange("billing_label") {
-		labels := d.Get("billing_label").(*schema.Set)
-		pool.BillingLabels = expandBillingLabel(labels)
-	}
+	// the labels are always sent, so that the default billing labels are kept
+	pool.BillingLabels = expandBillingLabel(d.Get("billing_label").(*schema.Set), client.DefaultBillingLabels)
 
 	if d.HasChange("global_ad_access") {
 		pool.GlobalILB = d.Get("global_ad_access").(bool)
@@ -284,9 +313,9 @@ func resourceGCPStoragePoolUpdate(d *schema.ResourceData, meta interface{}) erro
 		pool.Zone = d.Get("zone").(string)
 	}
 
-	err := client.updateStoragePool(&pool)
+	err = client.updateStoragePool(ctx, &pool)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	return resourceGCPStoragePoolRead(d, meta)
+	return resourceGCPStoragePoolRead(ctx, d, meta)
 }
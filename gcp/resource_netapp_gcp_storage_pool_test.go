@@ -1,11 +1,15 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"testing"
+	"time"
 
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/cvstest"
 )
 
 // Default go test timeout is 10min, which is too short for this test. Set to 20min
@@ -27,7 +31,7 @@ func TestAccStoragePoolZonal(t *testing.T) {
 		PreCheck: func() {
 			testAccPreCheck(t)
 		},
-		Providers: testAccProviders,
+		ProviderFactories: testAccProviderFactories,
 		// CheckDestroy: testAccCheckGCPStoragePoolDestroy,
 		Steps: []resource.TestStep{
 			{
@@ -80,6 +84,58 @@ func TestAccStoragePoolZonal(t *testing.T) {
 	})
 }
 
+func TestGCPStoragePoolImport_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	srv.Put("europe-west1/Pools/pool-1", map[string]interface{}{"name": "pool", "region": "europe-west1", "sizeInBytes": 1024 * GiBToBytes})
+	client := testFakeClient(srv)
+	client.Region = "us-east4"
+	r := resourceGCPStoragePool()
+
+	for _, id := range []string{"pool-1", "pool-1:europe-west1"} {
+		d := r.Data(&terraform.InstanceState{ID: id})
+		if diags := r.ReadContext(context.Background(), d, client); diags.HasError() {
+			t.Fatalf("import of %s failed: %v", id, diags)
+		}
+		if d.Id() != id || d.Get("region") != "europe-west1" || d.Get("name") != "pool" {
+			t.Errorf("unexpected import of %s: %s, %v", id, d.Id(), d.State())
+		}
+	}
+}
+
+func TestGCPStoragePoolDefaultBillingLabels_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	defer func(interval time.Duration) { jobPollMinInterval = interval }(jobPollMinInterval)
+	jobPollMinInterval = time.Millisecond
+	srv.Put("us-east4/Pools/pool-1", map[string]interface{}{"name": "pool", "region": "us-east4", "network": "default", "serviceLevel": "StandardSW",
+		"sizeInBytes": 1024 * GiBToBytes, "billingLabels": []interface{}{map[string]interface{}{"key": "cost_center", "value": "cc-42"}}})
+	client := testFakeClient(srv)
+	client.Region = "us-east4"
+	client.DefaultBillingLabels = map[string]string{"cost_center": "cc-42"}
+	r := resourceGCPStoragePool()
+	d := r.Data(&terraform.InstanceState{ID: "pool-1"})
+	if diags := r.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	config := map[string]interface{}{"name": "pool", "region": "us-east4", "network": "default", "service_level": "StandardSW", "size": 1024}
+
+	diff, err := r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config), client)
+	if err != nil || (diff != nil && diff.Attributes["effective_billing_labels.cost_center"] != nil) {
+		t.Fatalf("expected no billing label changes, got %v (%v)", diff, err)
+	}
+
+	// changing the default billing labels updates existing storage pools
+	client.DefaultBillingLabels = map[string]string{"cost_center": "cc-43"}
+	state := testFakeApply(t, r, d.State(), config, client)
+	if labels := testFakeBillingLabels(srv, "us-east4/Pools/pool-1"); !reflect.DeepEqual(labels, map[string]string{"cost_center": "cc-43"}) {
+		t.Fatalf("unexpected billing labels after changing the defaults %v", labels)
+	}
+	if state.Attributes["effective_billing_labels.cost_center"] != "cc-43" {
+		t.Fatalf("unexpected state %v", state.Attributes)
+	}
+}
+
 func testAccCheckGCPStoragePoolExists(name string, pool *storagePool) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		client := testAccProvider.Meta().(*Client)
@@ -92,7 +148,7 @@ func testAccCheckGCPStoragePoolExists(name string, pool *storagePool) resource.T
 		if rs.Primary.ID == "" {
 			return fmt.Errorf("No pool ID is set")
 		}
-		response, err := client.getStoragePoolByID(&storagePool{
+		response, err := client.getStoragePoolByID(context.Background(), &storagePool{
 			PoolID: rs.Primary.ID,
 			Region: rs.Primary.Attributes["region"],
 		})
@@ -148,3 +204,15 @@ func testAccStoragePoolConfigZonalUpdate(region, network string) string {
 	  }
   `, region, region+"-b", network)
 }
+
+func TestDecodeStoragePoolOperation(t *testing.T) {
+	pool, err := decodeStoragePoolOperation([]byte(`{"response": {"AnyValue": {"poolId": "pool-1", "region": "us-east4"}}}`))
+	if err != nil || pool.PoolID != "pool-1" || pool.Region != "us-east4" {
+		t.Fatalf("unexpected pool %v (%v)", pool, err)
+	}
+	for _, response := range []string{`{}`, `{"response": "done"}`, `{"response": {"AnyValue": null}}`, `[]`} {
+		if _, err := decodeStoragePoolOperation([]byte(response)); err == nil {
+			t.Errorf("expected an error for %s", response)
+		}
+	}
+}
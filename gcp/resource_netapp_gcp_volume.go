@@ -1,14 +1,16 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"regexp"
 	"strings"
 	"time"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/restapi"
 )
 
@@ -20,15 +22,21 @@ const TiBToGiB = 1024
 
 func resourceGCPVolume() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceGCPVolumeCreate,
-		Read:   resourceGCPVolumeRead,
-		Delete: resourceGCPVolumeDelete,
-		Update: resourceGCPVolumeUpdate,
-		Exists: resourceGCPVolumeExists,
+		// restores from a backup wait at least restoreTimeout instead of the create timeout
+		CreateWithoutTimeout: resourceGCPVolumeCreateWithTimeout,
+		ReadContext:          resourceGCPVolumeRead,
+		DeleteContext:        resourceGCPVolumeDelete,
+		UpdateContext:        resourceGCPVolumeUpdate,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 		CustomizeDiff: resourceVolumeCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -41,7 +49,8 @@ func resourceGCPVolume() *schema.Resource {
 			},
 			"region": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 			},
 			"protocol_types": {
 				Type:     schema.TypeList,
@@ -98,6 +107,38 @@ func resourceGCPVolume() *schema.Resource {
 					},
 				},
 			},
+			"backup_policy": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"enabled": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"daily_backups_to_keep": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      0,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"weekly_backups_to_keep": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      0,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"monthly_backups_to_keep": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      0,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+					},
+				},
+			},
 			"snapshot_policy": {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -357,10 +398,30 @@ func resourceGCPVolume() *schema.Resource {
 					},
 				},
 			},
+			"effective_billing_labels": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
 			"snapshot_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"backup_id"},
+			},
+			"backup_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"snapshot_id"},
+			},
+			"revert_to_snapshot_id": {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"acknowledge_revert_data_loss": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
 		},
 	}
 }
@@ -381,7 +442,44 @@ func TranslateServiceLevelState2API(slevel string) string {
 	return apiValue
 }
 
-func resourceGCPVolumeCreate(d *schema.ResourceData, meta interface{}) error {
+// TranslateServiceLevelAPI2State to translate the service level returned by the API to the resource value, due to the API bugs
+// API value: resource value
+// basic     : standard
+// standard  : premium
+// extreme   : extreme
+// It returns false with the API value for an unknown service level.
+func TranslateServiceLevelAPI2State(apiValue string) (string, bool) {
+	switch apiValue {
+	case "basic":
+		return "standard", true
+	case "standard":
+		return "premium", true
+	case "extreme":
+		return "extreme", true
+	}
+	return apiValue, false
+}
+
+// restoreTimeout is the minimum time to wait for the restore of a volume from a backup
+const restoreTimeout = 2 * time.Hour
+
+// resourceGCPVolumeCreateWithTimeout creates the volume within the create timeout, or within restoreTimeout
+// when the volume is restored from a backup and the create timeout is shorter
+func resourceGCPVolumeCreateWithTimeout(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, cancel := context.WithTimeout(ctx, volumeCreateTimeout(d))
+	defer cancel()
+	return resourceGCPVolumeCreate(ctx, d, meta)
+}
+
+func volumeCreateTimeout(d *schema.ResourceData) time.Duration {
+	timeout := d.Timeout(schema.TimeoutCreate)
+	if _, ok := d.GetOk("backup_id"); ok && timeout < restoreTimeout {
+		return restoreTimeout
+	}
+	return timeout
+}
+
+func resourceGCPVolumeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Creating volume: %v", d.Get("name").(string))
 
 	client := meta.(*Client)
@@ -389,7 +487,11 @@ func resourceGCPVolumeCreate(d *schema.ResourceData, meta interface{}) error {
 	volume := volumeRequest{}
 
 	volume.Name = d.Get("name").(string)
-	volume.Region = d.Get("region").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	volume.Region = region
 	volume.Network = d.Get("network").(string)
 	protocols := d.Get("protocol_types")
 	for _, protocol := range protocols.([]interface{}) {
@@ -414,6 +516,12 @@ func resourceGCPVolumeCreate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	if v, ok := d.GetOk("backup_policy"); ok {
+		if len(v.([]interface{})) > 0 {
+			volume.BackupPolicy = expandBackupPolicy(v.([]interface{})[0].(map[string]interface{}))
+		}
+	}
+
 	if v, ok := d.GetOk("volume_path"); ok {
 		volume.CreationToken = v.(string)
 	}
@@ -440,7 +548,7 @@ func resourceGCPVolumeCreate(d *schema.ResourceData, meta interface{}) error {
 		if policy.Len() > 0 {
 			resp, err := expandExportPolicy(policy, volume.StorageClass)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			volume.ExportPolicy = resp
 		}
@@ -462,7 +570,7 @@ func resourceGCPVolumeCreate(d *schema.ResourceData, meta interface{}) error {
 	// Commented out as temporary fix to accommodate pool volume.
 	// if volume.StorageClass == "software" && ((volume.Zone == "" && volume.RegionalHA == false) || (volume.Zone != "" && volume.RegionalHA == true)) {
 	// 	log.Print("Error creating volume")
-	// 	return fmt.Errorf("If storage_class is software, zone or RegionalHA is mandatory")
+	// 	return diag.Errorf("If storage_class is software, zone or RegionalHA is mandatory")
 	// }
 
 	if v, ok := d.GetOk("unix_permissions"); ok {
@@ -481,126 +589,149 @@ func resourceGCPVolumeCreate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
-	if v, ok := d.GetOk("billing_label"); ok {
-		values := v.(*schema.Set)
-		if values.Len() > 0 {
-			labels := make([]billingLabel, 0, values.Len())
-			for _, v := range expandBillingLabel(values) {
-				labels = append(labels, v)
-			}
-			volume.BillingLabels = labels
-		}
+	if labels := expandBillingLabel(d.Get("billing_label").(*schema.Set), client.DefaultBillingLabels); len(labels) > 0 {
+		volume.BillingLabels = labels
 	}
 
 	if v, ok := d.GetOk("snapshot_id"); ok {
 		volume.SnapshotID = v.(string)
 	}
 
+	if v, ok := d.GetOk("backup_id"); ok {
+		backup, err := client.getRegionVolumeBackupByID(ctx, volume.Region, v.(string))
+		if err != nil {
+			return diag.Errorf("Error restoring volume from backup: %s", err)
+		}
+		if backup.LifeCycleState != "available" {
+			return diag.Errorf("Error restoring volume from backup: backup %s is %s, it must be available", backup.VolumeBackupID, backup.LifeCycleState)
+		}
+		volume.BackupID = backup.VolumeBackupID
+	}
+
 	var res createVolumeResult
-	var err error
-	res, err = client.createVolume(&volume, volType)
+	res, err = client.createVolume(ctx, &volume, volType)
 	if err != nil {
 		log.Print("Error creating volume")
-		return err
+		return diag.FromErr(err)
 	}
 
 	var volumeRes volumeResult
-	time.Sleep(5 * time.Second)
+	if err := sleepWithContext(ctx, 5*time.Second); err != nil {
+		return diag.FromErr(err)
+	}
 	volume.Network = d.Get("network").(string)
-	volumeRes, err = validateVolumeExistsAfterCreate(client, volume, res.Name.JobID.VolID, volType)
+	volumeRes, err = validateVolumeExistsAfterCreate(ctx, client, volume, &res, volType)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(volumeRes.VolumeID)
 	if volumeRes.LifeCycleState == "available" {
-		return resourceGCPVolumeRead(d, meta)
+		return resourceGCPVolumeRead(ctx, d, meta)
 	}
-	volumeRes, err = waitForVolumeCreationComplete(client, volumeRes)
+	volumeRes, err = waitForVolumeCreationComplete(ctx, client, volumeRes, res.Name.JobID.Jobs, volumeCreateTimeout(d))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// if volume's state is error, delete the volume and retry for twice. If the operation still fails, return error.
 	if volumeRes.LifeCycleState == "error" {
 		retries := 2
 		for retries > 0 && volumeRes.LifeCycleState == "error" {
-			deleteErr := resourceGCPVolumeDelete(d, meta)
-			if deleteErr != nil {
-				return fmt.Errorf("failed to delete volume in error state after creation. %s", deleteErr.Error())
+			deleteDiags := resourceGCPVolumeDelete(ctx, d, meta)
+			if deleteDiags.HasError() {
+				return append(diag.Errorf("failed to delete volume in error state after creation"), deleteDiags...)
 			}
 			volume.Network = d.Get("network").(string)
-			res, err = client.createVolume(&volume, volType)
+			res, err = client.createVolume(ctx, &volume, volType)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
+			}
+			if err := sleepWithContext(ctx, 5*time.Second); err != nil {
+				return diag.FromErr(err)
 			}
-			time.Sleep(5 * time.Second)
 			volume.Network = d.Get("network").(string)
-			volumeRes, err = validateVolumeExistsAfterCreate(client, volume, res.Name.JobID.VolID, volType)
+			volumeRes, err = validateVolumeExistsAfterCreate(ctx, client, volume, &res, volType)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			d.SetId(volumeRes.VolumeID)
-			volumeRes, err = waitForVolumeCreationComplete(client, volumeRes)
+			volumeRes, err = waitForVolumeCreationComplete(ctx, client, volumeRes, res.Name.JobID.Jobs, volumeCreateTimeout(d))
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if volumeRes.LifeCycleState == "available" {
-				return resourceGCPVolumeRead(d, meta)
+				return resourceGCPVolumeRead(ctx, d, meta)
 			}
 			timeSleep := time.Duration(nextRandomInt(5, 10)) * time.Second
-			time.Sleep(timeSleep)
+			if err := sleepWithContext(ctx, timeSleep); err != nil {
+				return diag.FromErr(err)
+			}
 			retries--
 		}
 		if d.Get("delete_on_creation_error").(bool) {
-			deleteErr := resourceGCPVolumeDelete(d, meta)
-			if deleteErr != nil {
-				return fmt.Errorf("failed to delete volume in error state after creation. %s", deleteErr.Error())
+			deleteDiags := resourceGCPVolumeDelete(ctx, d, meta)
+			if deleteDiags.HasError() {
+				return append(diag.Errorf("failed to delete volume in error state after creation"), deleteDiags...)
 			}
-			return fmt.Errorf("%v. Volume in error state is deleted", volumeRes.LifeCycleStateDetails)
+			return diag.Errorf("%v. Volume in error state is deleted", volumeRes.LifeCycleStateDetails)
 		}
-		return fmt.Errorf("%v", volumeRes.LifeCycleStateDetails)
+		return diag.Errorf("%v", volumeRes.LifeCycleStateDetails)
 	}
-	return resourceGCPVolumeRead(d, meta)
+	return resourceGCPVolumeRead(ctx, d, meta)
 }
 
-// Wait up to 15 minutes for volume creation to complete.
-func waitForVolumeCreationComplete(client *Client, volumeRes volumeResult) (volumeResult, error) {
-	waitSeconds := 900    // first volume creation can take 11 minutes
-	threshold := 900 - 60 // when to warn
-	elapsed := time.Duration(0)
-	var err error
-	for waitSeconds > 0 && volumeRes.LifeCycleState == "creating" {
-		timeSleep := time.Duration(nextRandomInt(20, 30))
-		time.Sleep(timeSleep * time.Second)
-		elapsed = elapsed + timeSleep
-		volumeRes, err = client.getVolumeByID(volumeRequest{Region: volumeRes.Region, VolumeID: volumeRes.VolumeID})
+// Wait for the creation jobs, then up to timeout for volume creation to complete. The first volume creation can take 11 minutes.
+// A failed creation job is not an error here, the volume is returned in error state instead.
+func waitForVolumeCreationComplete(ctx context.Context, client *Client, volumeRes volumeResult, jobs []job, timeout time.Duration) (volumeResult, error) {
+	start := time.Now()
+	nextWarning := time.Minute // when to warn
+	err := client.waitForJobs(ctx, volumeRes.Region, jobs)
+	if err != nil {
+		if !isJobError(err) {
+			return volumeResult{}, err
+		}
+		log.Printf("Volume creation failed: %s", err)
+	}
+	if len(jobs) > 0 {
+		volumeRes, err = client.getVolumeByID(ctx, volumeRequest{Region: volumeRes.Region, VolumeID: volumeRes.VolumeID})
+		if err != nil {
+			return volumeResult{}, err
+		}
+	}
+	for time.Since(start) < timeout && volumeRes.LifeCycleState == "creating" {
+		timeSleep := time.Duration(nextRandomInt(20, 30)) * time.Second
+		if err := sleepWithContext(ctx, timeSleep); err != nil {
+			return volumeResult{}, err
+		}
+		volumeRes, err = client.getVolumeByID(ctx, volumeRequest{Region: volumeRes.Region, VolumeID: volumeRes.VolumeID})
 		if err != nil {
 			return volumeResult{}, err
 		}
-		if waitSeconds < threshold {
-			threshold = threshold - 60
-			log.Printf("Volume creation still in progress after %d seconds.\n", elapsed)
+		if elapsed := time.Since(start); elapsed >= nextWarning {
+			nextWarning = nextWarning + time.Minute
+			log.Printf("Volume creation still in progress after %d seconds.\n", int(elapsed.Seconds()))
 		}
-		waitSeconds = waitSeconds - int(timeSleep)
 	}
 	return volumeRes, nil
 }
 
 // A bug might be presented in the API. A volume creation request is acknowledged(volume ID is returned), but get volume by ID doesn't find any result.
 // A temporary fix is to send the create request again.
-func validateVolumeExistsAfterCreate(client *Client, volume volumeRequest, volumeID string, volType string) (volumeResult, error) {
-	volumeRes, err := client.getVolumeByID(volumeRequest{Region: volume.Region, VolumeID: volumeID})
-	var res createVolumeResult
+// res is updated when the volume is created again.
+func validateVolumeExistsAfterCreate(ctx context.Context, client *Client, volume volumeRequest, res *createVolumeResult, volType string) (volumeResult, error) {
+	volumeRes, err := client.getVolumeByID(ctx, volumeRequest{Region: volume.Region, VolumeID: res.Name.JobID.VolID})
 	network := volume.Network
 	retries := 3
 	if err != nil {
-		for err != nil && err.Error() == "code: 404, message: Error describing volume - Volume not found" && retries > 0 {
-			time.Sleep(20 * time.Second)
+		for restapi.IsNotFound(err) && retries > 0 {
+			if err := sleepWithContext(ctx, 20*time.Second); err != nil {
+				return volumeResult{}, err
+			}
 			volume.Network = network
-			res, err = client.createVolume(&volume, volType)
+			*res, err = client.createVolume(ctx, &volume, volType)
 			if err != nil {
 				return volumeResult{}, err
 			}
-			volumeRes, err = client.getVolumeByID(volumeRequest{Region: volume.Region, VolumeID: res.Name.JobID.VolID})
+			volumeRes, err = client.getVolumeByID(ctx, volumeRequest{Region: volume.Region, VolumeID: res.Name.JobID.VolID})
 			retries--
 		}
 		if err != nil {
@@ -610,42 +741,49 @@ func validateVolumeExistsAfterCreate(client *Client, volume volumeRequest, volum
 	return volumeRes, nil
 }
 
-func resourceGCPVolumeRead(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPVolumeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Reading volume: %#v", d)
 	client := meta.(*Client)
 
 	volume := volumeRequest{}
 
+	// without region, like on terraform import, the region is looked up by the ID
 	volume.Region = d.Get("region").(string)
 
 	id := d.Id()
 	volume.VolumeID = id
 
 	var res volumeResult
-	res, err := client.getVolumeByID(volume)
+	res, err := client.getVolumeByID(ctx, volume)
 	if err != nil {
-		return err
+		if restapi.IsNotFound(err) {
+			log.Printf("Volume %s not found, removing it from state", id)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
-	// Wait for 20 minutes if the volume is still in operation.
-	waitSeconds := 1200
-	for waitSeconds > 0 && (res.LifeCycleState == "creating" || res.LifeCycleState == "deleting" || res.LifeCycleState == "updating") {
-		time.Sleep(20 * time.Second)
-		res, err = client.getVolumeByID(volumeRequest{Region: volume.Region, VolumeID: id})
+	// Wait up to the read timeout if the volume is still in operation.
+	deadline := time.Now().Add(d.Timeout(schema.TimeoutRead))
+	for time.Now().Before(deadline) && (res.LifeCycleState == "creating" || res.LifeCycleState == "deleting" || res.LifeCycleState == "updating") {
+		if err := sleepWithContext(ctx, 20*time.Second); err != nil {
+			return diag.FromErr(err)
+		}
+		res, err = client.getVolumeByID(ctx, volumeRequest{Region: volume.Region, VolumeID: id})
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
-		waitSeconds = waitSeconds - 10
 	}
 
 	if res.VolumeID != id {
-		return fmt.Errorf("Expected Volume ID %v, Response contained Volume ID %v", id, res.VolumeID)
+		return diag.Errorf("Expected Volume ID %v, Response contained Volume ID %v", id, res.VolumeID)
 	}
 
 	if res.LifeCycleState == "error" {
-		return fmt.Errorf("Volume with name: %v and id: %v is in error state. Please manually delete the volume, make sure the config is correct and run terraform apply again. LifeCycleStateDetails: %v",
+		return diag.Errorf("Volume with name: %v and id: %v is in error state. Please manually delete the volume, make sure the config is correct and run terraform apply again. LifeCycleStateDetails: %v",
 			res.Name, res.VolumeID, res.LifeCycleStateDetails)
 	} else if res.LifeCycleState == "disabled" {
-		return fmt.Errorf("Volume with name: %v and id: %v is in disabled state. Please manually enable the volume and runn terraform apply again. LifeCycleStateDetails: %v",
+		return diag.Errorf("Volume with name: %v and id: %v is in disabled state. Please manually enable the volume and runn terraform apply again. LifeCycleStateDetails: %v",
 			res.Name, res.VolumeID, res.LifeCycleStateDetails)
 	} else if res.LifeCycleState == "deleted" {
 		d.SetId("")
@@ -653,34 +791,29 @@ func resourceGCPVolumeRead(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	if err := d.Set("name", res.Name); err != nil {
-		return fmt.Errorf("Error reading volume name: %s", err)
+		return diag.Errorf("Error reading volume name: %s", err)
 	}
 
 	if err := d.Set("size", res.Size/GiBToBytes); err != nil {
-		return fmt.Errorf("Error reading volume size: %s", err)
+		return diag.Errorf("Error reading volume size: %s", err)
 	}
 
 	log.Printf("**** API response service level is %s", res.ServiceLevel)
-	/*
-		There is a bug on API. Translate the response with right value
-		API Respose:  Real Value
-		basic      :  standard
-		standard   :  premium
-		extreme    :  extreme
-	*/
-	var slevel = res.ServiceLevel
-
-	if res.ServiceLevel == "basic" {
-		slevel = "standard"
-	} else if res.ServiceLevel == "standard" {
-		slevel = "premium"
+	var diags diag.Diagnostics
+	slevel, ok := TranslateServiceLevelAPI2State(res.ServiceLevel)
+	if !ok {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Unexpected volume service level",
+			Detail:   fmt.Sprintf("The API returned service level %q for volume %s, which cannot be translated to standard, premium or extreme. It is stored as is.", res.ServiceLevel, id),
+		})
 	}
 
 	if err := d.Set("service_level", slevel); err != nil {
-		return fmt.Errorf("Error reading volume service_level: %s", err)
+		return diag.Errorf("Error reading volume service_level: %s", err)
 	}
 	if err := d.Set("pool_id", res.PoolID); err != nil {
-		return fmt.Errorf("Error reading volume pool_id: %s", err)
+		return diag.Errorf("Error reading volume pool_id: %s", err)
 	}
 	for i, protocol := range res.ProtocolTypes {
 		if protocol == "CIFS" {
@@ -688,10 +821,10 @@ func resourceGCPVolumeRead(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 	if err := d.Set("protocol_types", res.ProtocolTypes); err != nil {
-		return fmt.Errorf("Error reading volume protocol_types: %s", err)
+		return diag.Errorf("Error reading volume protocol_types: %s", err)
 	}
 	if err := d.Set("volume_path", res.CreationToken); err != nil {
-		return fmt.Errorf("Error reading volume path or Creation Token: %s", err)
+		return diag.Errorf("Error reading volume path or Creation Token: %s", err)
 	}
 	// res.Network either contains simple network name or
 	// projects/${HOST_PROJECT_ID}/global/networks/${SHARED_VPC_NAME}, usually (but not exclusively) for shared VPC
@@ -706,49 +839,62 @@ func resourceGCPVolumeRead(d *schema.ResourceData, meta interface{}) error {
 		// if network path contains different projectId than our project, it is shared-VPC
 		if nws[1] != client.Project {
 			if err := d.Set("shared_vpc_project_number", nws[1]); err != nil {
-				return fmt.Errorf("Error reading shared_vpc_project_number: %s", err)
+				return diag.Errorf("Error reading shared_vpc_project_number: %s", err)
 			}
 		}
 	} else {
-		return fmt.Errorf("Error returned network path invalid: %s", res.Network)
+		return diag.Errorf("Error returned network path invalid: %s", res.Network)
 	}
 	if err := d.Set("network", network); err != nil {
-		return fmt.Errorf("Error reading volume network: %s", err)
+		return diag.Errorf("Error reading volume network: %s", err)
 	}
 	if err := d.Set("region", res.Region); err != nil {
-		return fmt.Errorf("Error reading volume region: %s", err)
+		return diag.Errorf("Error reading volume region: %s", err)
 	}
 	if _, ok := d.GetOk("storage_class"); ok {
 		if err := d.Set("storage_class", res.StorageClass); err != nil {
-			return fmt.Errorf("Error reading volume storage_class: %s", err)
+			return diag.Errorf("Error reading volume storage_class: %s", err)
 		}
 	}
 	snapshotPolicy := flattenSnapshotPolicy(res.SnapshotPolicy)
 	exportPolicy := flattenExportPolicy(res.ExportPolicy)
 	if err := d.Set("snapshot_policy", snapshotPolicy); err != nil {
-		return fmt.Errorf("Error reading volume snapshot_policy: %s", err)
+		return diag.Errorf("Error reading volume snapshot_policy: %s", err)
+	}
+	// a disabled backup policy is what is left after the backup_policy block is removed
+	if res.BackupPolicy != nil && !res.BackupPolicy.Enabled && len(d.Get("backup_policy").([]interface{})) == 0 {
+		res.BackupPolicy = nil
+	}
+	if err := d.Set("backup_policy", flattenBackupPolicy(res.BackupPolicy)); err != nil {
+		return diag.Errorf("Error reading volume backup_policy: %s", err)
+	}
+	// backup_id forces a new volume, so an imported restored volume must not lose it
+	if res.BackupID != "" {
+		if err := d.Set("backup_id", res.BackupID); err != nil {
+			return diag.Errorf("Error reading volume backup_id: %s", err)
+		}
 	}
 	if len(res.ExportPolicy.Rules) > 0 {
 		if err := d.Set("export_policy", exportPolicy); err != nil {
-			return fmt.Errorf("Error reading volume export_policy: %s", err)
+			return diag.Errorf("Error reading volume export_policy: %s", err)
 		}
 	} else {
 		a := schema.NewSet(schema.HashString, []interface{}{})
 		if err := d.Set("export_policy", a); err != nil {
-			return fmt.Errorf("Error reading volume export_policy: %s", err)
+			return diag.Errorf("Error reading volume export_policy: %s", err)
 		}
 	}
 	mountPoints := flattenMountPoints(res.MountPoints)
 	if err := d.Set("mount_points", mountPoints); err != nil {
-		return fmt.Errorf("Error reading volume mount_points: %s", err)
+		return diag.Errorf("Error reading volume mount_points: %s", err)
 	}
 	if _, ok := d.GetOk("zone"); ok {
 		if err := d.Set("zone", res.Zone); err != nil {
-			return fmt.Errorf("Error reading volume zone: %s", err)
+			return diag.Errorf("Error reading volume zone: %s", err)
 		}
 	}
 	if err := d.Set("snapshot_directory", res.SnapshotDirectory); err != nil {
-		return fmt.Errorf("Error reading volume snapshot_directory: %s", err)
+		return diag.Errorf("Error reading volume snapshot_directory: %s", err)
 	}
 	if v, ok := d.GetOk("smb_share_settings"); ok {
 		// There are a few default values in API, which means the API sets these values even they aren't specified in creation.
@@ -768,58 +914,72 @@ func resourceGCPVolumeRead(d *schema.ResourceData, meta interface{}) error {
 			}
 		}
 		if err := d.Set("smb_share_settings", currentSmbSettings); err != nil {
-			return fmt.Errorf("Error reading volume smb_share_settings: %s", err)
+			return diag.Errorf("Error reading volume smb_share_settings: %s", err)
 		}
 	}
 	if _, ok := d.GetOk("unix_permissions"); ok {
 		if err := d.Set("unix_permissions", res.UnixPermissions); err != nil {
-			return fmt.Errorf("Error reading volume unix_permissions: %s", err)
+			return diag.Errorf("Error reading volume unix_permissions: %s", err)
 		}
 	}
 	if _, ok := d.GetOk("security_style"); ok {
 		if err := d.Set("security_style", res.SecurityStyle); err != nil {
-			return fmt.Errorf("Error reading volume security_style: %s", err)
+			return diag.Errorf("Error reading volume security_style: %s", err)
 		}
 	}
 	if _, ok := d.GetOk("billing_label"); ok {
-		labels := flattenBillingLabel(res.BillingLabels)
+		labels := flattenBillingLabel(removeDefaultBillingLabels(res.BillingLabels, client.DefaultBillingLabels, d.Get("billing_label").(*schema.Set)))
 		if err := d.Set("billing_label", labels); err != nil {
-			return fmt.Errorf("Error reading volume billing_label: %s", err)
+			return diag.Errorf("Error reading volume billing_label: %s", err)
 		}
 	}
-	return nil
+	if err := d.Set("effective_billing_labels", flattenEffectiveBillingLabels(res.BillingLabels)); err != nil {
+		return diag.Errorf("Error reading volume effective_billing_labels: %s", err)
+	}
+	return diags
 }
 
-func resourceGCPVolumeDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPVolumeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Deleting volume: %#v", d)
 
 	volume := volumeRequest{}
 
-	volume.Region = d.Get("region").(string)
 	client := meta.(*Client)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	volume.Region = region
 
 	id := d.Id()
 	volume.VolumeID = id
 
-	deleteErr := client.deleteVolume(volume)
-	if deleteErr != nil {
-		return deleteErr
+	deleteErr := client.deleteVolume(ctx, volume)
+	if restapi.IsNotFound(deleteErr) {
+		return nil
+	}
+	if deleteErr != nil && !isJobError(deleteErr) {
+		return diag.FromErr(deleteErr)
 	}
 
-	getVolume, err := client.getVolumeByID(volume)
+	getVolume, err := client.getVolumeByID(ctx, volume)
 	if err != nil {
-		return err
+		if restapi.IsNotFound(err) {
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 	if getVolume.LifeCycleState == "deleted" {
 		return nil
 	} else if getVolume.LifeCycleState == "deleting" {
-		waitTime := 300
-		for waitTime > 0 {
-			time.Sleep(20 * time.Second)
-			waitTime = waitTime - 20
-			getVolume, err = client.getVolumeByID(volume)
+		deadline := time.Now().Add(d.Timeout(schema.TimeoutDelete))
+		for time.Now().Before(deadline) {
+			if err := sleepWithContext(ctx, 20*time.Second); err != nil {
+				return diag.FromErr(err)
+			}
+			getVolume, err = client.getVolumeByID(ctx, volume)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if getVolume.LifeCycleState == "deleted" {
 				return nil
@@ -833,67 +993,57 @@ func resourceGCPVolumeDelete(d *schema.ResourceData, meta interface{}) error {
 	if getVolume.LifeCycleState == "error" {
 		retries := 3
 		for getVolume.LifeCycleState == "error" && retries > 0 {
-			time.Sleep(time.Duration(nextRandomInt(5, 20)) * time.Second)
-			deleteErr := client.deleteVolume(volume)
-			if deleteErr != nil {
-				return deleteErr
+			if err := sleepWithContext(ctx, time.Duration(nextRandomInt(5, 20))*time.Second); err != nil {
+				return diag.FromErr(err)
+			}
+			deleteErr := client.deleteVolume(ctx, volume)
+			if deleteErr != nil && !isJobError(deleteErr) {
+				return diag.FromErr(deleteErr)
 			}
-			getVolume, err = client.getVolumeByID(volume)
+			getVolume, err = client.getVolumeByID(ctx, volume)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		}
 		if getVolume.LifeCycleState == "error" {
-			return fmt.Errorf("error deleting volume with id: %s, name: %s; %s", getVolume.VolumeID, getVolume.Name, getVolume.LifeCycleStateDetails)
+			return diag.Errorf("error deleting volume with id: %s, name: %s; %s", getVolume.VolumeID, getVolume.Name, getVolume.LifeCycleStateDetails)
 		}
 	}
 
 	return nil
 }
 
-func resourceGCPVolumeExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	log.Printf("Checking existence of volume: %#v", d)
-	client := meta.(*Client)
-
-	volume := volumeRequest{}
-
-	id := d.Id()
-	volume.VolumeID = id
-	volume.Region = d.Get("region").(string)
-	var res volumeResult
-	res, err := client.getVolumeByID(volume)
-	if err != nil {
-		if err, ok := err.(*restapi.ResponseError); ok {
-			if err.Name == "xUnknown" {
-				d.SetId("")
-				return false, nil
-			}
-			return false, err
-		}
-		return false, err
-	}
-
-	if res.VolumeID != id {
-		d.SetId("")
-		return false, nil
-	}
-
-	return true, nil
-}
-
-func resourceGCPVolumeUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPVolumeUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Updating volume: %#v\n", d)
 	makechange := 0
 	client := meta.(*Client)
 	volume := volumeRequest{}
 	volume.VolumeID = d.Id()
-	volume.Region = d.Get("region").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	volume.Region = region
 	volume.Name = d.Get("name").(string)
 	// size is always required.
 	volume.Size = d.Get("size").(int) * GiBToBytes
 	volume.SnapshotDirectory = d.Get("snapshot_directory").(bool)
 	volume.StorageClass = d.Get("storage_class").(string)
 
+	// revert first, so that the other changes apply to the reverted volume
+	if d.HasChange("revert_to_snapshot_id") {
+		if snapshotID := d.Get("revert_to_snapshot_id").(string); snapshotID != "" {
+			if err := revertVolumeToSnapshot(ctx, client, d, volume.Region, snapshotID); err != nil {
+				// the planned value is saved to state on errors, keep the old one so that the revert is planned again
+				old, _ := d.GetChange("revert_to_snapshot_id")
+				if err := d.Set("revert_to_snapshot_id", old); err != nil {
+					log.Printf("Error resetting revert_to_snapshot_id: %s", err)
+				}
+				return diag.Errorf("Error reverting volume %s to snapshot %s: %s", d.Id(), snapshotID, err)
+			}
+		}
+	}
+
 	if d.HasChange("name") {
 		makechange = 1
 	}
@@ -914,11 +1064,21 @@ func resourceGCPVolumeUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	if d.HasChange("backup_policy") {
+		if policies := d.Get("backup_policy").([]interface{}); len(policies) > 0 {
+			volume.BackupPolicy = expandBackupPolicy(policies[0].(map[string]interface{}))
+		} else {
+			// removing the backup_policy block stops the scheduled backups
+			volume.BackupPolicy = &backupPolicy{Enabled: false}
+		}
+		makechange = 1
+	}
+
 	if d.HasChange("export_policy") {
 		policy := d.Get("export_policy").(*schema.Set)
 		resp, err := expandExportPolicy(policy, volume.StorageClass)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		volume.ExportPolicy = resp
 		makechange = 1
@@ -953,26 +1113,63 @@ func resourceGCPVolumeUpdate(d *schema.ResourceData, meta interface{}) error {
 		volume.SecurityStyle = d.Get("security_style").(string)
 	}
 
-	if d.HasChange("billing_label") {
+	// the labels are always sent, so that the default billing labels are kept
+	volume.BillingLabels = expandBillingLabel(d.Get("billing_label").(*schema.Set), client.DefaultBillingLabels)
+	if d.HasChange("billing_label") || d.HasChange("effective_billing_labels") {
 		makechange = 1
-		labels := d.Get("billing_label").(*schema.Set)
-		volume.BillingLabels = expandBillingLabel(labels)
 	}
 
 	if makechange == 1 {
 		log.Println("Make change on volume")
-		err := client.updateVolume(volume)
+		err := client.updateVolume(ctx, volume)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	} else {
 		log.Println("NOT updateVolume")
 	}
 
-	return resourceGCPVolumeRead(d, meta)
+	return resourceGCPVolumeRead(ctx, d, meta)
+}
+
+// revertVolumeToSnapshot reverts the volume to one of its own snapshots, after checking that the data loss was acknowledged
+func revertVolumeToSnapshot(ctx context.Context, client *Client, d *schema.ResourceData, region string, snapshotID string) error {
+	if !d.Get("acknowledge_revert_data_loss").(bool) {
+		return fmt.Errorf("acknowledge_revert_data_loss must be true, data written after the snapshot is lost")
+	}
+	snapshot, err := client.getSnapshotByID(ctx, listSnapshotRequest{Region: region, VolumeID: d.Id(), SnapshotID: snapshotID})
+	if err != nil {
+		if restapi.IsNotFound(err) {
+			return fmt.Errorf("the snapshot does not belong to the volume")
+		}
+		return err
+	}
+	if snapshot.SnapshotID == "" {
+		return fmt.Errorf("the snapshot is deleted")
+	}
+	if snapshot.VolumeID != "" && snapshot.VolumeID != d.Id() {
+		return fmt.Errorf("the snapshot does not belong to the volume")
+	}
+	if snapshot.LifeCycleState != "available" {
+		return fmt.Errorf("the snapshot is %s, it must be available", snapshot.LifeCycleState)
+	}
+	log.Printf("Reverting volume %s to snapshot %s", d.Id(), snapshotID)
+	return client.revertVolume(ctx, revertVolumeRequest{Region: region, VolumeID: d.Id(), SnapshotID: snapshotID})
 }
 
-func resourceVolumeCustomizeDiff(diff *schema.ResourceDiff, v interface{}) error {
+func resourceVolumeCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, v interface{}) error {
+	// a new volume has no snapshots to revert to, the revert would be silently skipped
+	if diff.Id() == "" {
+		if snapshotID := diff.Get("revert_to_snapshot_id").(string); snapshotID != "" {
+			return fmt.Errorf("revert_to_snapshot_id (%s) cannot be set when the volume is created, set it after the volume exists", snapshotID)
+		}
+	}
+	// reverting loses the data written after the snapshot, refuse to plan it without acknowledgement
+	if diff.Id() != "" && diff.HasChange("revert_to_snapshot_id") {
+		if snapshotID := diff.Get("revert_to_snapshot_id").(string); snapshotID != "" && !diff.Get("acknowledge_revert_data_loss").(bool) {
+			return fmt.Errorf("acknowledge_revert_data_loss must be true to revert the volume to snapshot %s, data written after the snapshot is lost", snapshotID)
+		}
+	}
 	if diff.HasChange("storage_class") {
 		current, expect := diff.GetChange("storage_class")
 		if current.(string) == "" {
@@ -989,5 +1186,5 @@ func resourceVolumeCustomizeDiff(diff *schema.ResourceDiff, v interface{}) error
 			}
 		}
 	}
-	return nil
+	return customizeDiffEffectiveBillingLabels(ctx, diff, v)
 }
@@ -1,50 +1,97 @@
 package gcp
 
 import (
-	"fmt"
+	"context"
 	"log"
 	"time"
 
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/restapi"
 )
 
 func resourceGCPVolumeBackup() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceGCPVolumeBackupCreate,
-		Read:   resourceGCPVolumeBackupRead,
-		Delete: resourceGCPVolumeBackupDelete,
-		Exists: resourceGCPVolumeBackupExists,
+		CreateContext: resourceGCPVolumeBackupCreate,
+		ReadContext:   resourceGCPVolumeBackupRead,
+		DeleteContext: resourceGCPVolumeBackupDelete,
+		UpdateContext: resourceGCPVolumeBackupUpdate,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: importVolumeItemState("<region>:<volume_id>:<backup_id>"),
+		},
+		Timeouts: &schema.ResourceTimeout{
+			// create waits for the data transfer of the backup
+			Create: schema.DefaultTimeout(2 * time.Hour),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 			},
 			"region": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 				ForceNew: true,
 			},
 			"volume_name": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				AtLeastOneOf: []string{"volume_id", "volume_name", "creation_token"},
 			},
 			"creation_token": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				AtLeastOneOf: []string{"volume_id", "volume_name", "creation_token"},
+			},
+			"volume_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				AtLeastOneOf:  []string{"volume_id", "volume_name", "creation_token"},
+				ConflictsWith: []string{"volume_name", "creation_token"},
+			},
+			"bytes_transferred": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"created": {
 				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Computed: true,
+			},
+			"completed": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"backup_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"lifecycle_state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"lifecycle_state_details": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"backup_region": {
+				Type:     schema.TypeString,
+				Computed: true,
 			},
 		},
 	}
 }
 
-func resourceGCPVolumeBackupCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPVolumeBackupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Creating volume backup: %#v", d)
 
 	client := meta.(*Client)
@@ -52,158 +99,174 @@ func resourceGCPVolumeBackupCreate(d *schema.ResourceData, meta interface{}) err
 	volumeBackup := createVolumeBackupRequest{}
 
 	volumeBackup.Name = d.Get("name").(string)
-	volumeBackup.Region = d.Get("region").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	volumeBackup.Region = region
 
 	volume := volumeRequest{}
 	volume.Region = volumeBackup.Region
 
 	volume.Name = d.Get("volume_name").(string)
 	volume.CreationToken = d.Get("creation_token").(string)
+	volume.VolumeID = d.Get("volume_id").(string)
 
 	// Check the volume status. Start creating backup when volume is ready to use
-	retries := 0
-	for {
-		volresult, err := client.getVolumeByNameOrCreationToken(volume)
-		if err != nil {
-			log.Print("Error getting volume ID")
-			return err
-		}
-		if volresult.LifeCycleStateDetails != "Available for use" {
-			if retries < 30 {
-				log.Printf("Volume %s is not ready. Wait for 10 seconds and check again.\n", volume.Name)
-				time.Sleep(10 * time.Second)
-				retries++
-			} else {
-				log.Printf("Volume %s is not ready.\n", volume.Name)
-				return err
-			}
-		} else {
-			volumeBackup.VolumeID = volresult.VolumeID
-			break
-		}
+	volresult, err := client.waitForVolumeAvailable(ctx, volume, d.Timeout(schema.TimeoutCreate), 10*time.Second)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	volumeBackup.VolumeID = volresult.VolumeID
+	if err := setVolumeItemVolume(d, volresult); err != nil {
+		return diag.FromErr(err)
 	}
 
-	res, err := client.createVolumeBackup(&volumeBackup)
+	res, err := client.createVolumeBackup(ctx, &volumeBackup)
 	if err != nil {
 		log.Print("Error creating VolumeBackup")
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(res.Name.JobID.VolumeBackupID)
 	log.Printf("Created VolumeBackup: %v", volumeBackup.Name)
 
-	return resourceGCPVolumeBackupRead(d, meta)
+	// the backup transfers data after the create job is done
+	_, err = client.waitForVolumeBackupAvailable(ctx, listVolumeBackupRequest{
+		Region:         volumeBackup.Region,
+		VolumeID:       volumeBackup.VolumeID,
+		VolumeBackupID: d.Id(),
+	}, 10*time.Second)
+	if err != nil {
+		return diag.Errorf("Error waiting for volume backup %s: %s", d.Id(), err)
+	}
+
+	return resourceGCPVolumeBackupRead(ctx, d, meta)
 }
 
-func resourceGCPVolumeBackupRead(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPVolumeBackupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Reading VolumeBackup: %#v", d)
 	client := meta.(*Client)
 
 	volumeBackup := listVolumeBackupRequest{}
 
-	volumeBackup.Region = d.Get("region").(string)
-
-	volume := volumeRequest{}
-	volume.Region = volumeBackup.Region
-	volume.Name = d.Get("volume_name").(string)
-	volume.CreationToken = d.Get("creation_token").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	volumeBackup.Region = region
+	if err := d.Set("region", region); err != nil {
+		return diag.Errorf("Error reading volume backup region: %s", err)
+	}
 
-	volresult, err := client.getVolumeByNameOrCreationToken(volume)
+	volumeID, err := getVolumeItemVolumeID(ctx, d, client, volumeBackup.Region)
 	if err != nil {
 		log.Print("Error getting volume ID")
-		return err
+		return diag.FromErr(err)
 	}
-
-	volumeBackup.VolumeID = volresult.VolumeID
+	volumeBackup.VolumeID = volumeID
 
 	id := d.Id()
 	volumeBackup.VolumeBackupID = id
 	var res listVolumeBackupResult
-	res, err = client.getVolumeBackupByID(volumeBackup)
+	res, err = client.getVolumeBackupByID(ctx, volumeBackup)
 	if err != nil {
+		if restapi.IsNotFound(err) {
+			log.Printf("VolumeBackup %s not found, removing it from state", id)
+			d.SetId("")
+			return nil
+		}
 		log.Print("Error getting VolumeBackup")
-		return err
+		return diag.FromErr(err)
 	}
 
+	if res.VolumeBackupID == "" {
+		log.Printf("VolumeBackup %s is deleted, removing it from state", id)
+		d.SetId("")
+		return nil
+	}
 	if res.VolumeBackupID != id {
-		return fmt.Errorf("Expected VolumeBackup ID %v, Response contained VolumeBackup ID %v", id, res.VolumeBackupID)
+		return diag.Errorf("Expected VolumeBackup ID %v, Response contained VolumeBackup ID %v", id, res.VolumeBackupID)
+	}
+
+	attributes := map[string]interface{}{
+		"name":                    res.Name,
+		"volume_id":               volumeID,
+		"bytes_transferred":       res.BytesTransferred,
+		"created":                 res.Created,
+		"completed":               res.Completed,
+		"backup_type":             res.BackupType,
+		"lifecycle_state":         res.LifeCycleState,
+		"lifecycle_state_details": res.LifeCycleStateDetails,
+		"backup_region":           res.storedIn(region),
+	}
+	for k, v := range attributes {
+		if err := d.Set(k, v); err != nil {
+			return diag.Errorf("Error reading volume backup %s: %s", k, err)
+		}
 	}
 
 	return nil
 }
 
-func resourceGCPVolumeBackupDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPVolumeBackupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Deleting VolumeBackup: %#v", d)
 
 	client := meta.(*Client)
 
 	volumeBackup := deleteVolumeBackupRequest{}
 
-	volumeBackup.Region = d.Get("region").(string)
-
-	volume := volumeRequest{}
-	volume.Region = volumeBackup.Region
-	volume.Name = d.Get("volume_name").(string)
-	volume.CreationToken = d.Get("creation_token").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	volumeBackup.Region = region
 
-	volresult, err := client.getVolumeByNameOrCreationToken(volume)
+	volumeID, err := getVolumeItemVolumeID(ctx, d, client, volumeBackup.Region)
 	if err != nil {
 		log.Print("Error getting volume ID")
-		return err
+		return diag.FromErr(err)
 	}
-
-	volumeBackup.VolumeID = volresult.VolumeID
+	volumeBackup.VolumeID = volumeID
 
 	id := d.Id()
 	volumeBackup.VolumeBackupID = id
 
-	deleteErr := client.deleteVolumeBackup(volumeBackup)
-	if deleteErr != nil {
-		return deleteErr
+	deleteErr := client.deleteVolumeBackup(ctx, volumeBackup)
+	if deleteErr != nil && !restapi.IsNotFound(deleteErr) {
+		return diag.FromErr(deleteErr)
 	}
 
 	return nil
 }
 
-func resourceGCPVolumeBackupExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	log.Printf("Checking existence of VolumeBackup: %#v", d)
-	client := meta.(*Client)
+func resourceGCPVolumeBackupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("Updating VolumeBackup: %#v", d)
 
-	volumeBackup := listVolumeBackupRequest{}
-
-	id := d.Id()
-	volumeBackup.VolumeBackupID = id
-	volumeBackup.Region = d.Get("region").(string)
-
-	volume := volumeRequest{}
-	volume.Region = volumeBackup.Region
-	volume.Name = d.Get("volume_name").(string)
-	volume.CreationToken = d.Get("creation_token").(string)
+	client := meta.(*Client)
 
-	volresult, err := client.getVolumeByNameOrCreationToken(volume)
+	volumeBackup := updateVolumeBackupRequest{}
+	volumeBackup.VolumeBackupID = d.Id()
+	volumeBackup.Name = d.Get("name").(string)
+	region, err := getRegion(d, client)
 	if err != nil {
-		log.Print("Error getting volume ID")
-		return false, err
+		return diag.FromErr(err)
 	}
+	volumeBackup.Region = region
 
-	volumeBackup.VolumeID = volresult.VolumeID
-
-	var res listVolumeBackupResult
-	res, err = client.getVolumeBackupByID(volumeBackup)
+	volumeID, err := getVolumeItemVolumeID(ctx, d, client, volumeBackup.Region)
 	if err != nil {
-		if err, ok := err.(*restapi.ResponseError); ok {
-			if err.Name == "xUnknown" {
-				d.SetId("")
-				return false, nil
-			}
-		}
-		return false, err
+		log.Print("Error getting volume ID")
+		return diag.FromErr(err)
 	}
+	volumeBackup.VolumeID = volumeID
 
-	if res.VolumeBackupID != id {
-		d.SetId("")
-		return false, nil
+	// only the name can be updated, this also corrects a backup renamed outside of terraform
+	if err := client.updateVolumeBackup(ctx, volumeBackup); err != nil {
+		return diag.FromErr(err)
 	}
 
-	return true, nil
+	log.Printf("Updated VolumeBackup: %v", volumeBackup.Name)
+
+	return resourceGCPVolumeBackupRead(ctx, d, meta)
 }
@@ -4,12 +4,16 @@ package gcp
 // The trick is to have a "primer" volume already created (outside of the AT).  The second volume creation takes 1 or 2 minutes.
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"strings"
 	"testing"
+	"time"
 
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/cvstest"
 )
 
 func TestAccVolumeBackup_basic(t *testing.T) {
@@ -19,8 +23,8 @@ func TestAccVolumeBackup_basic(t *testing.T) {
 		PreCheck: func() {
 			testAccPreCheck(t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckGCPVolumeBackupDestroy,
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGCPVolumeBackupDestroy,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccVolumeBackupConfigCreate(),
@@ -43,7 +47,7 @@ func testAccCheckGCPVolumeBackupDestroy(state *terraform.State) error {
 	for _, rs := range state.RootModule().Resources {
 		if rs.Type == "netapp-gcp_volume" {
 			volumeID = rs.Primary.ID
-			response, err := client.getVolumeByID(volumeRequest{
+			response, err := client.getVolumeByID(context.Background(), volumeRequest{
 				VolumeID: volumeID,
 				Region:   rs.Primary.Attributes["region"],
 			})
@@ -59,7 +63,7 @@ func testAccCheckGCPVolumeBackupDestroy(state *terraform.State) error {
 	}
 	volumeBackup.VolumeID = volumeID
 	var response listVolumeBackupResult
-	response, err := client.getVolumeBackupByID(volumeBackup)
+	response, err := client.getVolumeBackupByID(context.Background(), volumeBackup)
 	if err != nil {
 		return err
 	}
@@ -93,7 +97,7 @@ func testAccCheckGCPVolumeBackupExists(name string, volumeBU *listVolumeBackupRe
 		volume.Name = rs.Primary.Attributes["volume_name"]
 		volume.CreationToken = rs.Primary.Attributes["creation_token"]
 
-		volresult, err := client.getVolumeByNameOrCreationToken(volume)
+		volresult, err := client.getVolumeByNameOrCreationToken(context.Background(), volume)
 		if err != nil {
 			log.Print("Error getting volume ID")
 			return err
@@ -103,7 +107,7 @@ func testAccCheckGCPVolumeBackupExists(name string, volumeBU *listVolumeBackupRe
 
 		volumeBackup.VolumeBackupID = rs.Primary.ID
 		var response listVolumeBackupResult
-		response, err = client.getVolumeBackupByID(volumeBackup)
+		response, err = client.getVolumeBackupByID(context.Background(), volumeBackup)
 		if err != nil {
 			return err
 		}
@@ -147,3 +151,95 @@ func testAccVolumeBackupConfigCreate() string {
 	}
   `)
 }
+
+func TestGCPVolumeBackupImport_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	srv.Put("us-east1/Volumes/vol-1", map[string]interface{}{"name": "test-volume", "creationToken": "test-volume-token"})
+	srv.Put("us-east1/Volumes/vol-1/Backups/backup-1", map[string]interface{}{"name": "terraform-acceptance-test-1", "volumeId": "vol-1"})
+	client := testFakeClient(srv)
+	r := resourceGCPVolumeBackup()
+
+	d := r.Data(&terraform.InstanceState{ID: "us-east1:vol-1:backup-1"})
+	imported, err := r.Importer.StateContext(context.Background(), d, client)
+	if err != nil {
+		t.Fatalf("import failed: %s", err)
+	}
+	if diags := r.ReadContext(context.Background(), imported[0], client); diags.HasError() {
+		t.Fatalf("read after import failed: %v", diags)
+	}
+	state := imported[0].State()
+	if state.ID != "backup-1" || state.Attributes["region"] != "us-east1" || state.Attributes["name"] != "terraform-acceptance-test-1" ||
+		state.Attributes["volume_name"] != "test-volume" || state.Attributes["creation_token"] != "test-volume-token" {
+		t.Fatalf("unexpected state after import %v", state)
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{"name": "terraform-acceptance-test-1", "region": "us-east1", "creation_token": "test-volume-token"})
+	if diff, err := r.Diff(context.Background(), state, config, client); err != nil || !diff.Empty() {
+		t.Errorf("unexpected plan after import %v: %v", diff, err)
+	}
+}
+
+func TestGCPVolumeBackup_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	defer func(interval time.Duration) { jobPollMinInterval = interval }(jobPollMinInterval)
+	jobPollMinInterval = time.Millisecond
+	srv.Put("us-east1/Volumes/vol-1", map[string]interface{}{"name": "test-volume", "creationToken": "test-volume", "lifeCycleStateDetails": "Available for use"})
+	client := testFakeClient(srv)
+	r := resourceGCPVolumeBackup()
+	config := map[string]interface{}{"name": "terraform-acceptance-test-1", "region": "us-east1", "volume_name": "test-volume"}
+
+	state := testFakeApply(t, r, nil, config, client)
+	if state.ID == "" || state.Attributes["volume_id"] != "vol-1" || state.Attributes["lifecycle_state"] != "available" {
+		t.Fatalf("unexpected state after create %v", state)
+	}
+
+	// a backup renamed outside of terraform is renamed back
+	backup := srv.Get("us-east1/Volumes/vol-1/Backups/" + state.ID)
+	backup["name"] = "renamed"
+	backup["bytesTransferred"] = 4096
+	backup["created"] = "2026-10-18T08:00:00Z"
+	backup["completed"] = "2026-10-18T08:05:00Z"
+	backup["backupType"] = "manual"
+	srv.Put("us-east1/Volumes/vol-1/Backups/"+state.ID, backup)
+	d := r.Data(state)
+	if diags := resourceGCPVolumeBackupRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if d.Get("name") != "renamed" || d.Get("bytes_transferred") != 4096 || d.Get("created") != "2026-10-18T08:00:00Z" ||
+		d.Get("completed") != "2026-10-18T08:05:00Z" || d.Get("backup_type") != "manual" {
+		t.Fatalf("unexpected state after read %v", d.State())
+	}
+	id := state.ID
+	state = testFakeApply(t, r, d.State(), config, client)
+	if state.ID != id || state.Attributes["name"] != "terraform-acceptance-test-1" {
+		t.Fatalf("unexpected state after update %v", state)
+	}
+	if backup := srv.Get("us-east1/Volumes/vol-1/Backups/" + id); backup["name"] != "terraform-acceptance-test-1" {
+		t.Fatalf("backup was not renamed %v", backup)
+	}
+
+	if state = testFakeApply(t, r, state, nil, client); state != nil {
+		t.Fatalf("unexpected state after destroy %v", state)
+	}
+}
+
+func TestWaitForVolumeBackupAvailable_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	srv.Put("us-east1/Volumes/vol-1/Backups/failed", map[string]interface{}{"lifeCycleState": "error", "lifeCycleStateDetails": "transfer failed"})
+	srv.Put("us-east1/Volumes/vol-1/Backups/stuck", map[string]interface{}{"lifeCycleState": "creating"})
+	client := testFakeClient(srv)
+
+	_, err := client.waitForVolumeBackupAvailable(context.Background(), listVolumeBackupRequest{Region: "us-east1", VolumeID: "vol-1", VolumeBackupID: "failed"}, time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "transfer failed") {
+		t.Errorf("unexpected error for a failed backup: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.waitForVolumeBackupAvailable(ctx, listVolumeBackupRequest{Region: "us-east1", VolumeID: "vol-1", VolumeBackupID: "stuck"}, 10*time.Millisecond); err == nil {
+		t.Errorf("expected a timeout for a backup which does not become available")
+	}
+}
@@ -1,24 +1,31 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/restapi"
 )
 
 func resourceGCPVolumeReplication() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceGCPVolumeReplicationCreate,
-		Read:   resourceGCPVolumeReplicationRead,
-		Delete: resourceGCPVolumeReplicationDelete,
-		Update: resourceGCPVolumeReplicationUpdate,
-		Exists: resourceGCPVolumeReplicationExists,
+	r := &schema.Resource{
+		CreateContext: resourceGCPVolumeReplicationCreate,
+		ReadContext:   resourceGCPVolumeReplicationRead,
+		DeleteContext: resourceGCPVolumeReplicationDelete,
+		UpdateContext: resourceGCPVolumeReplicationUpdate,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -40,7 +47,8 @@ func resourceGCPVolumeReplication() *schema.Resource {
 			},
 			"region": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 			},
 			"name": {
 				Type:     schema.TypeString,
@@ -62,11 +70,77 @@ func resourceGCPVolumeReplication() *schema.Resource {
 				Optional:     true,
 				ValidateFunc: validation.StringInSlice([]string{"64Mbps", "128Mbps", "256Mbps"}, true),
 			},
+			"desired_state": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "mirrored",
+				ValidateFunc: validation.StringInSlice([]string{"mirrored", "broken", "suspended"}, false),
+			},
+			"reversed": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 		},
 	}
+	for k, v := range volumeReplicationStatusSchema() {
+		r.Schema[k] = v
+	}
+	return r
+}
+
+// volumeReplicationStatusSchema are the computed attributes describing the health of a volume replication
+func volumeReplicationStatusSchema() map[string]*schema.Schema {
+	s := map[string]*schema.Schema{}
+	for _, k := range []string{"mirror_state", "relationship_status", "lifecycle_state", "lifecycle_state_details", "last_transfer_end_time"} {
+		s[k] = &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		}
+	}
+	for _, k := range []string{"last_transfer_size", "last_transfer_duration", "total_transfer_bytes", "lag_time"} {
+		s[k] = &schema.Schema{
+			Type:     schema.TypeInt,
+			Computed: true,
+		}
+	}
+	return s
+}
+
+// flattenVolumeReplicationStatus returns the attributes of volumeReplicationStatusSchema
+func flattenVolumeReplicationStatus(res volumeReplicationResult) map[string]interface{} {
+	return map[string]interface{}{
+		"mirror_state":            res.MirrorState,
+		"relationship_status":     res.RelationshipStatus,
+		"lifecycle_state":         res.LifeCycleState,
+		"lifecycle_state_details": res.LifeCycleStateDetails,
+		"last_transfer_end_time":  res.TransferStats.LastTransferEndTime,
+		"last_transfer_size":      res.TransferStats.LastTransferSize,
+		"last_transfer_duration":  res.TransferStats.LastTransferDuration,
+		"total_transfer_bytes":    res.TransferStats.TotalTransferBytes,
+		"lag_time":                res.TransferStats.LagTime,
+	}
+}
+
+// replicationStateActions are the actions which take a volume replication from one desired_state to another
+var replicationStateActions = map[string]map[string][]string{
+	"mirrored":  {"broken": {"Break"}, "suspended": {"Suspend"}},
+	"broken":    {"mirrored": {"Resync"}, "suspended": {"Resync", "Suspend"}},
+	"suspended": {"mirrored": {"Resume"}, "broken": {"Break"}},
+}
+
+// changeVolumeReplicationState runs the actions which take the volume replication from one desired_state to another
+func changeVolumeReplicationState(ctx context.Context, client *Client, replica *volumeReplicationRequest, from string, to string) error {
+	for _, action := range replicationStateActions[from][to] {
+		log.Printf("Running %s on volume replication %s to change it from %s to %s", action, replica.ReplicationID, from, to)
+		if err := client.volumeReplicationAction(ctx, replica, action); err != nil {
+			return fmt.Errorf("Error changing volume replication %s from %s to %s: %s", replica.ReplicationID, from, to, err)
+		}
+	}
+	return nil
 }
 
-func resourceGCPVolumeReplicationCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPVolumeReplicationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Creating volume replication: %#v", d)
 
 	client := meta.(*Client)
@@ -74,7 +148,11 @@ func resourceGCPVolumeReplicationCreate(d *schema.ResourceData, meta interface{}
 	replica := volumeReplicationRequest{}
 
 	replica.Name = d.Get("name").(string)
-	replica.Region = d.Get("region").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	replica.Region = region
 
 	if v, ok := d.GetOk("destination_volume_id"); ok {
 		replica.DestinationVolumeID = v.(string)
@@ -102,25 +180,37 @@ func resourceGCPVolumeReplicationCreate(d *schema.ResourceData, meta interface{}
 	}
 	log.Printf("here here here here here: %#v", replica)
 
-	res, err := client.createVolumeReplication(&replica)
+	res, err := client.createVolumeReplication(ctx, &replica)
 	if err != nil {
 		log.Print("Error creating volume replication")
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(res.ReplicationID)
 	log.Printf("Created volume replication: %v", res.Name)
 
-	return resourceGCPVolumeReplicationRead(d, meta)
+	replica.ReplicationID = res.ReplicationID
+	if err := changeVolumeReplicationState(ctx, client, &replica, "mirrored", d.Get("desired_state").(string)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceGCPVolumeReplicationRead(ctx, d, meta)
 }
 
-func resourceGCPVolumeReplicationRead(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPVolumeReplicationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Reading volume replication: %#v", d)
 	client := meta.(*Client)
 
 	replication := volumeReplicationRequest{}
 
-	replication.Region = d.Get("region").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	replication.Region = region
+	if err := d.Set("region", region); err != nil {
+		return diag.Errorf("Error reading volume replication region: %s", err)
+	}
 
 	id := d.Id()
 	replication.ReplicationID = id
@@ -128,116 +218,139 @@ func resourceGCPVolumeReplicationRead(d *schema.ResourceData, meta interface{})
 	var res volumeReplicationResult
 	for {
 		var replica volumeReplicationResult
-		replica, err := client.getVolumeReplicationByID(replication)
+		replica, err := client.getVolumeReplicationByID(ctx, replication)
 		if err != nil {
-			return err
+			if restapi.IsNotFound(err) {
+				log.Printf("Volume replication %s not found, removing it from state", id)
+				d.SetId("")
+				return nil
+			}
+			return diag.FromErr(err)
 		}
 
+		if replica.ReplicationID == "" {
+			log.Printf("Volume replication %s is deleted, removing it from state", id)
+			d.SetId("")
+			return nil
+		}
 		if replica.ReplicationID != id {
-			return fmt.Errorf("Expected replication ID %v, Response contained replication ID %v", id, res.ReplicationID)
+			return diag.Errorf("Expected replication ID %v, Response contained replication ID %v", id, replica.ReplicationID)
 		}
 
 		if replica.LifeCycleState == "error" {
-			return fmt.Errorf("Volume replication %v is in %v state. Please check the setup. Will delete the volume replication",
+			return diag.Errorf("Volume replication %v is in %v state. Please check the setup. Will delete the volume replication",
 				replica.ReplicationID, replica.LifeCycleState)
 		} else if replica.LifeCycleState == "available" {
 			res = replica
 			break
 		} else {
-			time.Sleep(time.Duration(2) * time.Second)
+			if err := sleepWithContext(ctx, 2*time.Second); err != nil {
+				return diag.FromErr(err)
+			}
 		}
 	}
 
-	if err := d.Set("destination_volume_id", res.DestinationVolumeID); err != nil {
-		return fmt.Errorf("Error reading destination volume id: %s", err)
+	// source_volume_id and destination_volume_id keep the direction the replication was created with
+	sourceVolumeID, destinationVolumeID := res.SourceVolumeID, res.DestinationVolumeID
+	if d.Get("reversed").(bool) {
+		sourceVolumeID, destinationVolumeID = destinationVolumeID, sourceVolumeID
+	}
+
+	if err := d.Set("destination_volume_id", destinationVolumeID); err != nil {
+		return diag.Errorf("Error reading destination volume id: %s", err)
 	}
 
-	if err := d.Set("source_volume_id", res.SourceVolumeID); err != nil {
-		return fmt.Errorf("Error reading source volume id: %s", err)
+	if err := d.Set("source_volume_id", sourceVolumeID); err != nil {
+		return diag.Errorf("Error reading source volume id: %s", err)
 	}
 
 	if err := d.Set("remote_region", res.RemoteRegion); err != nil {
-		return fmt.Errorf("Error reading source volume id: %s", err)
+		return diag.Errorf("Error reading source volume id: %s", err)
 	}
 
 	if err := d.Set("endpoint_type", res.EndpointType); err != nil {
-		return fmt.Errorf("Error reading source volume endpoint_type: %s", err)
+		return diag.Errorf("Error reading source volume endpoint_type: %s", err)
 	}
 
 	if err := d.Set("policy", res.Policy); err != nil {
-		return fmt.Errorf("Error reading policy: %s", err)
+		return diag.Errorf("Error reading policy: %s", err)
 	}
 
 	if err := d.Set("schedule", res.Schedule); err != nil {
-		return fmt.Errorf("Error reading schedule: %s", err)
+		return diag.Errorf("Error reading schedule: %s", err)
 	}
 
 	if err := d.Set("bandwidth", res.Bandwidth); err != nil {
-		return fmt.Errorf("Error reading bandwidth: %s", err)
+		return diag.Errorf("Error reading bandwidth: %s", err)
+	}
+
+	for k, v := range flattenVolumeReplicationStatus(res) {
+		if err := d.Set(k, v); err != nil {
+			return diag.Errorf("Error reading volume replication %s: %s", k, err)
+		}
+	}
+
+	// a replication broken or suspended outside of terraform shows a diff to the configured desired_state
+	if err := d.Set("desired_state", volumeReplicationState(res)); err != nil {
+		return diag.Errorf("Error reading desired state: %s", err)
 	}
 	return nil
 }
 
-func resourceGCPVolumeReplicationDelete(d *schema.ResourceData, meta interface{}) error {
+// volumeReplicationState returns the desired_state the volume replication is in
+func volumeReplicationState(res volumeReplicationResult) string {
+	switch {
+	case res.MirrorState == "broken":
+		return "broken"
+	case res.RelationshipStatus == "quiesced" || res.RelationshipStatus == "quiescing":
+		return "suspended"
+	}
+	return "mirrored"
+}
+
+func resourceGCPVolumeReplicationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Deleting volume replication: %#v", d)
 
 	replica := volumeReplicationRequest{}
 
-	replica.Region = d.Get("region").(string)
 	client := meta.(*Client)
-
-	id := d.Id()
-	replica.ReplicationID = id
-
-	err := client.breakVolumeReplication(&replica)
-	if err != nil {
-		return err
-	}
-	err = client.deleteVolumeReplication(&replica)
+	region, err := getRegion(d, client)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	//TODO: Need to wait until replication is really deleted, otherwise follow on volume deletes might fail
-
-	return nil
-}
-
-func resourceGCPVolumeReplicationExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	log.Printf("Checking existence of volume replication: %#v", d)
-	client := meta.(*Client)
-
-	replica := volumeReplicationRequest{}
+	replica.Region = region
 
 	id := d.Id()
 	replica.ReplicationID = id
-	replica.Region = d.Get("region").(string)
-	var res volumeReplicationResult
-	res, err := client.getVolumeReplicationByID(replica)
-	if err != nil {
-		if err, ok := err.(*restapi.ResponseError); ok {
-			if err.Name == "xUnknown" {
-				d.SetId("")
-				return false, nil
-			}
-			return false, err
+
+	if d.Get("desired_state").(string) != "broken" {
+		err = client.breakVolumeReplication(ctx, &replica)
+		if restapi.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return diag.FromErr(err)
 		}
-		return false, err
 	}
-
-	if res.ReplicationID != id {
-		d.SetId("")
-		return false, nil
+	err = client.deleteVolumeReplication(ctx, &replica)
+	if err != nil && !restapi.IsNotFound(err) {
+		return diag.FromErr(err)
 	}
+	//TODO: Need to wait until replication is really deleted, otherwise follow on volume deletes might fail
 
-	return true, nil
+	return nil
 }
 
-func resourceGCPVolumeReplicationUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceGCPVolumeReplicationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("Updating volume replication: %#v\n", d)
 	client := meta.(*Client)
 	replica := volumeReplicationRequest{}
 	replica.ReplicationID = d.Id()
-	replica.Region = d.Get("region").(string)
+	region, err := getRegion(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	replica.Region = region
 
 	if d.HasChange("schedule") {
 		replica.Schedule = d.Get("schedule").(string)
@@ -255,9 +368,29 @@ func resourceGCPVolumeReplicationUpdate(d *schema.ResourceData, meta interface{}
 		replica.Bandwidth = d.Get("bandwidth").(string)
 	}
 
-	err := client.updateVolumeReplication(&replica)
-	if err != nil {
-		return err
+	if d.HasChanges("schedule", "name", "policy", "bandwidth") {
+		err = client.updateVolumeReplication(ctx, &replica)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 	}
-	return resourceGCPVolumeReplicationRead(d, meta)
+
+	oldState, newState := d.GetChange("desired_state")
+	state := oldState.(string)
+	if d.HasChange("reversed") {
+		// a replication is reversed from broken, and is mirrored in the other direction afterwards
+		if err := changeVolumeReplicationState(ctx, client, &replica, state, "broken"); err != nil {
+			return diag.FromErr(err)
+		}
+		log.Printf("Reversing volume replication %s", replica.ReplicationID)
+		if err := client.volumeReplicationAction(ctx, &replica, "Reverse"); err != nil {
+			return diag.Errorf("Error reversing volume replication %s: %s", replica.ReplicationID, err)
+		}
+		state = "mirrored"
+	}
+	if err := changeVolumeReplicationState(ctx, client, &replica, state, newState.(string)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceGCPVolumeReplicationRead(ctx, d, meta)
 }
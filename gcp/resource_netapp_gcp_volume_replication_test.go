@@ -0,0 +1,132 @@
+package gcp
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/cvstest"
+)
+
+func TestGCPVolumeReplicationDesiredState_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	defer func(interval time.Duration) { jobPollMinInterval = interval }(jobPollMinInterval)
+	jobPollMinInterval = time.Millisecond
+	client := testFakeClient(srv)
+	r := resourceGCPVolumeReplication()
+	config := map[string]interface{}{"name": "replica", "region": "us-east4", "remote_region": "us-west2", "endpoint_type": "dst",
+		"source_volume_id": "source-1", "destination_volume_id": "destination-1", "schedule": "hourly"}
+
+	// actions returns the actions run on the volume replication since the last call
+	seen := 0
+	actions := func() []string {
+		requests := srv.Requests()
+		var result []string
+		for _, request := range requests[seen:] {
+			if strings.HasPrefix(request, "POST ") && strings.Count(request, "/") == 3 {
+				result = append(result, request[strings.LastIndex(request, "/")+1:])
+			}
+		}
+		seen = len(requests)
+		return result
+	}
+
+	state := testFakeApply(t, r, nil, config, client)
+	if state.Attributes["desired_state"] != "mirrored" || state.Attributes["reversed"] != "false" || len(actions()) != 0 {
+		t.Fatalf("unexpected state after create %v", state)
+	}
+	id := state.ID
+
+	steps := []struct {
+		desiredState string
+		reversed     bool
+		actions      []string
+		mirrorState  string
+	}{
+		{"broken", false, []string{"Break"}, "broken"},
+		{"mirrored", false, []string{"Resync"}, "mirrored"},
+		{"suspended", false, []string{"Suspend"}, "mirrored"},
+		{"mirrored", false, []string{"Resume"}, "mirrored"},
+		{"suspended", false, []string{"Suspend"}, "mirrored"},
+		{"mirrored", true, []string{"Break", "Reverse"}, "mirrored"},
+	}
+	for _, step := range steps {
+		config["desired_state"] = step.desiredState
+		config["reversed"] = step.reversed
+		state = testFakeApply(t, r, state, config, client)
+		if got := actions(); !reflect.DeepEqual(got, step.actions) {
+			t.Fatalf("expected actions %v for %s, got %v", step.actions, step.desiredState, got)
+		}
+		replication := srv.Get("us-east4/VolumeReplications/" + id)
+		if state.Attributes["desired_state"] != step.desiredState || replication["mirrorState"] != step.mirrorState ||
+			state.Attributes["mirror_state"] != step.mirrorState || (state.Attributes["relationship_status"] == "quiesced") != (step.desiredState == "suspended") {
+			t.Fatalf("unexpected state %v for replication %v", state, replication)
+		}
+	}
+
+	// the replication now copies destination-1 to source-1, but the configuration does not change
+	if replication := srv.Get("us-east4/VolumeReplications/" + id); replication["sourceVolumeUUID"] != "destination-1" {
+		t.Fatalf("replication was not reversed %v", replication)
+	}
+	if state.Attributes["source_volume_id"] != "source-1" || state.Attributes["destination_volume_id"] != "destination-1" {
+		t.Fatalf("unexpected state after reverse %v", state)
+	}
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), client)
+	if err != nil || !diff.Empty() {
+		t.Fatalf("expected no changes after reverse, got %v (%v)", diff, err)
+	}
+
+	config["desired_state"] = "broken"
+	state = testFakeApply(t, r, state, config, client)
+	actions()
+	if state = testFakeApply(t, r, state, nil, client); state != nil {
+		t.Fatalf("unexpected state after destroy %v", state)
+	}
+	if got := actions(); len(got) != 0 {
+		t.Fatalf("expected a broken replication to be deleted without actions, got %v", got)
+	}
+}
+
+func TestGCPVolumeReplicationDeleted_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	srv.Put("us-east4/VolumeReplications/replica-1", map[string]interface{}{"name": "replica", "lifeCycleState": "deleting"})
+	client := testFakeClient(srv)
+	r := resourceGCPVolumeReplication()
+
+	d := r.Data(&terraform.InstanceState{ID: "replica-1", Attributes: map[string]string{"region": "us-east4"}})
+	if diags := resourceGCPVolumeReplicationRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Fatalf("expected a deleting replication to be removed from state, got %s", d.Id())
+	}
+}
+
+func TestGCPVolumeReplicationStateDrift_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	client := testFakeClient(srv)
+	r := resourceGCPVolumeReplication()
+	config := map[string]interface{}{"name": "replica", "region": "us-east4", "remote_region": "us-west2", "endpoint_type": "dst",
+		"source_volume_id": "source-1", "destination_volume_id": "destination-1", "schedule": "hourly"}
+
+	for mirrorState, relationshipStatus := range map[string]string{"broken": "idle", "mirrored": "quiesced"} {
+		// the replication was broken or suspended outside of terraform
+		srv.Put("us-east4/VolumeReplications/replica-1", map[string]interface{}{"name": "replica", "sourceVolumeUUID": "source-1", "destinationVolumeUUID": "destination-1",
+			"remoteRegion": "us-west2", "endpointType": "dst", "replicationSchedule": "hourly", "replicationPolicy": "MirrorAllSnapshots",
+			"mirrorState": mirrorState, "relationshipStatus": relationshipStatus})
+		d := r.Data(&terraform.InstanceState{ID: "replica-1", Attributes: map[string]string{"region": "us-east4", "desired_state": "mirrored"}})
+		if diags := resourceGCPVolumeReplicationRead(context.Background(), d, client); diags.HasError() {
+			t.Fatalf("read failed: %v", diags)
+		}
+		diff, err := r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config), client)
+		if err != nil || diff == nil || diff.Attributes["desired_state"] == nil || diff.Attributes["desired_state"].New != "mirrored" {
+			t.Errorf("expected a diff back to mirrored from %s/%s, got %v (%v)", mirrorState, relationshipStatus, diff, err)
+		}
+	}
+}
@@ -1,13 +1,16 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/netapp/terraform-provider-netapp-gcp/gcp/cvs/cvstest"
 )
 
 func TestAccVolume_basic(t *testing.T) {
@@ -17,8 +20,8 @@ func TestAccVolume_basic(t *testing.T) {
 		PreCheck: func() {
 			testAccPreCheck(t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckGCPVolumeDestroy,
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGCPVolumeDestroy,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccVolumeConfigCreate(),
@@ -94,7 +97,7 @@ func testAccCheckGCPVolumeDestroy(state *terraform.State) error {
 		if rs.Type != "netapp-gcp_volume" {
 			continue
 		}
-		response, err := client.getVolumeByID(volumeRequest{
+		response, err := client.getVolumeByID(context.Background(), volumeRequest{
 			VolumeID: rs.Primary.ID,
 			Region:   rs.Primary.Attributes["region"],
 		})
@@ -120,7 +123,7 @@ func testAccCheckGCPVolumeExists(name string, volume *volumeResult) resource.Tes
 		if rs.Primary.ID == "" {
 			return fmt.Errorf("No volume ID is set")
 		}
-		response, err := client.getVolumeByID(volumeRequest{
+		response, err := client.getVolumeByID(context.Background(), volumeRequest{
 			VolumeID: rs.Primary.ID,
 			Region:   rs.Primary.Attributes["region"],
 		})
@@ -329,3 +332,296 @@ func testCheckResourceAttr(name string, key string, value string) resource.TestC
 		return nil
 	}
 }
+
+func TestGCPVolumeRestoreFromBackup_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	defer func(interval time.Duration) { jobPollMinInterval = interval }(jobPollMinInterval)
+	jobPollMinInterval = time.Millisecond
+	srv.Put("us-east4/Volumes/vol-1/Backups/backup-1", map[string]interface{}{"name": "nightly", "volumeId": "vol-1", "lifeCycleState": "available"})
+	srv.Put("us-east4/Volumes/vol-1/Backups/backup-2", map[string]interface{}{"name": "running", "volumeId": "vol-1", "lifeCycleState": "creating"})
+
+	client := testFakeClient(srv)
+	client.Region = Region
+	r := resourceGCPVolume()
+	config := func(backupID string) map[string]interface{} {
+		return map[string]interface{}{"name": "restored", "protocol_types": []interface{}{"NFSv3"}, "network": "default", "size": 1024, "backup_id": backupID}
+	}
+
+	for _, backupID := range []string{"backup-2", "unknown"} {
+		diff, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config(backupID)), client)
+		if err != nil {
+			t.Fatalf("plan failed: %s", err)
+		}
+		if _, diags := r.Apply(context.Background(), nil, diff, client); !diags.HasError() {
+			t.Errorf("expected restore from %s to fail", backupID)
+		}
+	}
+	for _, request := range srv.Requests() {
+		if strings.HasPrefix(request, "POST") {
+			t.Fatalf("unexpected request %s for a backup which is not available", request)
+		}
+	}
+
+	state := testFakeApply(t, r, nil, config("backup-1"), client)
+	if volume := srv.Get("us-east4/Volumes/" + state.ID); volume == nil || volume["backupId"] != "backup-1" || volume["snapshotId"] != "" {
+		t.Fatalf("unexpected volume %v", volume)
+	}
+	if state.Attributes["backup_id"] != "backup-1" || state.Attributes["name"] != "restored" {
+		t.Errorf("unexpected state after restore %v", state)
+	}
+
+	// restoring another backup replaces the volume
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config("backup-2")), client)
+	if err != nil || !diff.RequiresNew() {
+		t.Errorf("expected a change of backup_id to replace the volume, got %v (%v)", diff, err)
+	}
+}
+
+func TestVolumeCreateTimeout(t *testing.T) {
+	cases := []struct {
+		backupID string
+		create   time.Duration
+		expected time.Duration
+	}{
+		{"", 0, 20 * time.Minute},
+		{"", 3 * time.Hour, 3 * time.Hour},
+		{"backup-1", 0, restoreTimeout},
+		{"backup-1", 30 * time.Minute, restoreTimeout},
+		{"backup-1", 3 * time.Hour, 3 * time.Hour},
+	}
+	for _, c := range cases {
+		r := resourceGCPVolume()
+		if c.create != 0 {
+			r.Timeouts.Create = &c.create
+		}
+		d := r.Data(nil)
+		if err := d.Set("backup_id", c.backupID); err != nil {
+			t.Fatal(err)
+		}
+		if timeout := volumeCreateTimeout(d); timeout != c.expected {
+			t.Errorf("create timeout with backup %q and create timeout %v is %v, expected %v", c.backupID, c.create, timeout, c.expected)
+		}
+	}
+}
+
+func TestGCPVolumeRevert_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	defer func(interval time.Duration) { jobPollMinInterval = interval }(jobPollMinInterval)
+	jobPollMinInterval = time.Millisecond
+	srv.Put("us-east4/Volumes/vol-1/Snapshots/snap-1", map[string]interface{}{"name": "release-1", "volumeId": "vol-1"})
+	srv.Put("us-east4/Volumes/vol-2/Snapshots/snap-2", map[string]interface{}{"name": "other", "volumeId": "vol-2"})
+
+	client := testFakeClient(srv)
+	client.Region = Region
+	r := resourceGCPVolume()
+	state := testFakeVolumeState(t, srv, client, nil)
+	config := func(snapshotID string, acknowledge bool) map[string]interface{} {
+		return map[string]interface{}{"name": "config", "protocol_types": []interface{}{"NFSv3"}, "network": "default", "size": 1024,
+			"revert_to_snapshot_id": snapshotID, "acknowledge_revert_data_loss": acknowledge}
+	}
+
+	if _, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config("snap-1", false)), client); err == nil {
+		t.Errorf("expected the plan to fail without acknowledge_revert_data_loss")
+	}
+	if _, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config("snap-1", true)), client); err == nil {
+		t.Errorf("expected the plan to fail with revert_to_snapshot_id on create")
+	}
+
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config("snap-2", true)), client)
+	if err != nil {
+		t.Fatalf("plan failed: %s", err)
+	}
+	if _, diags := r.Apply(context.Background(), state, diff, client); !diags.HasError() {
+		t.Errorf("expected the revert to a snapshot of another volume to fail")
+	}
+
+	state = testFakeApply(t, r, state, config("snap-1", true), client)
+	if state.Attributes["revert_to_snapshot_id"] != "snap-1" {
+		t.Errorf("unexpected state after revert %v", state)
+	}
+	var reverts []string
+	for _, request := range srv.Requests() {
+		if strings.HasSuffix(request, "/Revert") {
+			reverts = append(reverts, request)
+		}
+	}
+	if len(reverts) != 1 || reverts[0] != "POST us-east4/Volumes/vol-1/Revert" {
+		t.Errorf("unexpected revert requests %v", reverts)
+	}
+}
+
+// testFakeVolumeState puts the volume vol-1 named config on the fake server, and returns its state as read by the resource
+func testFakeVolumeState(t *testing.T, srv *cvstest.Server, client *Client, attributes map[string]interface{}) *terraform.InstanceState {
+	t.Helper()
+	volume := map[string]interface{}{"name": "config", "creationToken": "config", "quotaInBytes": 1024 * GiBToBytes,
+		"protocolTypes": []string{"NFSv3"}, "network": "projects/123456789/global/networks/default", "lifeCycleStateDetails": "Available for use"}
+	for k, v := range attributes {
+		volume[k] = v
+	}
+	srv.Put("us-east4/Volumes/vol-1", volume)
+	d := resourceGCPVolume().Data(&terraform.InstanceState{ID: "vol-1"})
+	if diags := resourceGCPVolumeRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	return d.State()
+}
+
+func TestGCPVolumeRevertFailure_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	defer func(interval time.Duration) { jobPollMinInterval = interval }(jobPollMinInterval)
+	jobPollMinInterval = time.Millisecond
+	srv.Put("us-east4/Volumes/vol-1/Snapshots/snap-1", map[string]interface{}{"name": "release-1", "volumeId": "vol-1"})
+
+	client := testFakeClient(srv)
+	client.Region = Region
+	r := resourceGCPVolume()
+	state := testFakeVolumeState(t, srv, client, nil)
+	config := map[string]interface{}{"name": "config", "protocol_types": []interface{}{"NFSv3"}, "network": "default", "size": 1024,
+		"revert_to_snapshot_id": "snap-1", "acknowledge_revert_data_loss": true}
+
+	srv.InjectFailure(cvstest.Failure{Method: "POST", Path: "*/Volumes/*/Revert", StatusCode: 500, Message: "Revert failed"})
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), client)
+	if err != nil {
+		t.Fatalf("plan failed: %s", err)
+	}
+	state, diags := r.Apply(context.Background(), state, diff, client)
+	if !diags.HasError() {
+		t.Fatalf("expected the revert to fail")
+	}
+
+	// the failed revert is planned again
+	diff, err = r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), client)
+	if err != nil {
+		t.Fatalf("plan failed: %s", err)
+	}
+	if diff == nil || diff.Attributes["revert_to_snapshot_id"] == nil || diff.Attributes["revert_to_snapshot_id"].New != "snap-1" {
+		t.Fatalf("expected the revert to be pending, got %v", diff)
+	}
+	state = testFakeApply(t, r, state, config, client)
+	if state.Attributes["revert_to_snapshot_id"] != "snap-1" {
+		t.Errorf("unexpected state after revert %v", state)
+	}
+}
+
+// testFakeBillingLabels returns the billing labels of a resource of the fake server as a map
+func testFakeBillingLabels(srv *cvstest.Server, p string) map[string]string {
+	labels := make(map[string]string)
+	list, _ := srv.Get(p)["billingLabels"].([]interface{})
+	for _, l := range list {
+		label := l.(map[string]interface{})
+		labels[label["key"].(string)] = label["value"].(string)
+	}
+	return labels
+}
+
+func TestGCPVolumeDefaultBillingLabels_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	defer func(interval time.Duration) { jobPollMinInterval = interval }(jobPollMinInterval)
+	jobPollMinInterval = time.Millisecond
+	client := testFakeClient(srv)
+	client.Region = Region
+	client.DefaultBillingLabels = map[string]string{"cost_center": "cc-42"}
+	r := resourceGCPVolume()
+	state := testFakeVolumeState(t, srv, client, map[string]interface{}{"billingLabels": []interface{}{map[string]interface{}{"key": "cost_center", "value": "cc-42"}}})
+	config := map[string]interface{}{"name": "config", "protocol_types": []interface{}{"NFSv3"}, "network": "default", "size": 1024,
+		"billing_label": []interface{}{map[string]interface{}{"key": "team", "value": "storage"}}}
+
+	state = testFakeApply(t, r, state, config, client)
+	if labels := testFakeBillingLabels(srv, "us-east4/Volumes/vol-1"); !reflect.DeepEqual(labels, map[string]string{"team": "storage", "cost_center": "cc-42"}) {
+		t.Fatalf("unexpected billing labels %v", labels)
+	}
+	if state.Attributes["billing_label.#"] != "1" || state.Attributes["effective_billing_labels.cost_center"] != "cc-42" {
+		t.Fatalf("unexpected state %v", state.Attributes)
+	}
+
+	// an update which does not change billing_label keeps the default billing labels
+	config["size"] = 2048
+	state = testFakeApply(t, r, state, config, client)
+	if labels := testFakeBillingLabels(srv, "us-east4/Volumes/vol-1"); !reflect.DeepEqual(labels, map[string]string{"team": "storage", "cost_center": "cc-42"}) {
+		t.Fatalf("unexpected billing labels after resize %v", labels)
+	}
+
+	// changing the default billing labels updates existing volumes
+	client.DefaultBillingLabels = map[string]string{"cost_center": "cc-43", "owner": "infra"}
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), client)
+	if err != nil {
+		t.Fatalf("plan failed: %s", err)
+	}
+	if diff == nil || diff.Attributes["effective_billing_labels.cost_center"] == nil || diff.Attributes["effective_billing_labels.cost_center"].New != "cc-43" {
+		t.Fatalf("expected a plan to update the default billing labels, got %v", diff)
+	}
+	testFakeApply(t, r, state, config, client)
+	if labels := testFakeBillingLabels(srv, "us-east4/Volumes/vol-1"); !reflect.DeepEqual(labels, map[string]string{"team": "storage", "cost_center": "cc-43", "owner": "infra"}) {
+		t.Fatalf("unexpected billing labels after changing the defaults %v", labels)
+	}
+}
+
+func TestGCPVolumeBackupPolicy_fake(t *testing.T) {
+	srv := cvstest.NewServer()
+	defer srv.Close()
+	defer func(interval time.Duration) { jobPollMinInterval = interval }(jobPollMinInterval)
+	jobPollMinInterval = time.Millisecond
+	client := testFakeClient(srv)
+	client.Region = Region
+	r := resourceGCPVolume()
+	state := testFakeVolumeState(t, srv, client, nil)
+	if state.Attributes["backup_policy.#"] != "0" {
+		t.Fatalf("unexpected backup policy %v", state.Attributes)
+	}
+	config := func(policy map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"name": "config", "protocol_types": []interface{}{"NFSv3"}, "network": "default", "size": 1024,
+			"backup_policy": []interface{}{policy}}
+	}
+
+	state = testFakeApply(t, r, state, config(map[string]interface{}{"daily_backups_to_keep": 7, "weekly_backups_to_keep": 4}), client)
+	expected := map[string]interface{}{"enabled": true, "dailyBackupsToKeep": float64(7), "weeklyBackupsToKeep": float64(4), "monthlyBackupsToKeep": float64(0)}
+	if policy := srv.Get("us-east4/Volumes/vol-1")["backupPolicy"]; !reflect.DeepEqual(policy, expected) {
+		t.Fatalf("unexpected backup policy %v", policy)
+	}
+	if state.Attributes["backup_policy.0.daily_backups_to_keep"] != "7" || state.Attributes["backup_policy.0.enabled"] != "true" {
+		t.Fatalf("unexpected state %v", state.Attributes)
+	}
+
+	// a backup policy changed outside of terraform is detected and corrected
+	state = testFakeVolumeState(t, srv, client, map[string]interface{}{"backupPolicy": map[string]interface{}{"enabled": true, "dailyBackupsToKeep": 2}})
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config(map[string]interface{}{"daily_backups_to_keep": 7, "weekly_backups_to_keep": 4})), client)
+	if err != nil || diff.Empty() {
+		t.Fatalf("expected a plan to correct the backup policy: %v %v", diff, err)
+	}
+	state = testFakeApply(t, r, state, config(map[string]interface{}{"daily_backups_to_keep": 7, "weekly_backups_to_keep": 4}), client)
+	if policy := srv.Get("us-east4/Volumes/vol-1")["backupPolicy"]; !reflect.DeepEqual(policy, expected) {
+		t.Fatalf("unexpected backup policy %v", policy)
+	}
+
+	state = testFakeApply(t, r, state, config(map[string]interface{}{"enabled": false}), client)
+	if policy := srv.Get("us-east4/Volumes/vol-1")["backupPolicy"].(map[string]interface{}); policy["enabled"] != false {
+		t.Fatalf("backup policy was not disabled %v", policy)
+	}
+
+	// removing the backup_policy block disables the backup policy
+	state = testFakeApply(t, r, state, config(map[string]interface{}{"daily_backups_to_keep": 7}), client)
+	withoutPolicy := config(nil)
+	delete(withoutPolicy, "backup_policy")
+	state = testFakeApply(t, r, state, withoutPolicy, client)
+	if policy := srv.Get("us-east4/Volumes/vol-1")["backupPolicy"].(map[string]interface{}); policy["enabled"] != false {
+		t.Fatalf("backup policy was not disabled %v", policy)
+	}
+	if state.Attributes["backup_policy.#"] != "0" {
+		t.Fatalf("unexpected state %v", state.Attributes)
+	}
+	diff, err = r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(withoutPolicy), client)
+	if err != nil {
+		t.Fatalf("plan failed: %s", err)
+	}
+	if diff != nil {
+		for k := range diff.Attributes {
+			if strings.HasPrefix(k, "backup_policy") {
+				t.Fatalf("expected no backup policy changes, got %v", diff.Attributes)
+			}
+		}
+	}
+}
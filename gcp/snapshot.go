@@ -1,6 +1,7 @@
 package gcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -39,8 +40,13 @@ type deleteSnapshotRequest struct {
 
 // listSnapshotResult lists the volume for given Snapshot ID
 type listSnapshotResult struct {
-	SnapshotID     string `json:"snapshotId"`
-	LifeCycleState string `json:"lifeCycleState"`
+	SnapshotID            string `json:"snapshotId"`
+	Name                  string `json:"name"`
+	VolumeID              string `json:"volumeId"`
+	Created               string `json:"created"`
+	UsedBytes             int64  `json:"usedBytes"`
+	LifeCycleState        string `json:"lifeCycleState"`
+	LifeCycleStateDetails string `json:"lifeCycleStateDetails"`
 }
 
 // listSnapshotRequest requests the volume for given Snapshot ID and region
@@ -58,21 +64,16 @@ type updateSnapshotRequest struct {
 	SnapshotID string `structs:"snapshotId"`
 }
 
-func (c *Client) getSnapshotByID(snapshot listSnapshotRequest) (listSnapshotResult, error) {
+func (c *Client) getSnapshotByID(ctx context.Context, snapshot listSnapshotRequest) (listSnapshotResult, error) {
 
 	baseURL := fmt.Sprintf("%s/Volumes/%s/Snapshots/%s", snapshot.Region, snapshot.VolumeID, snapshot.SnapshotID)
 
-	statusCode, response, err := c.CallAPIMethod("GET", baseURL, nil)
+	_, response, err := c.CallAPIMethod(ctx, "GET", baseURL, nil)
 	if err != nil {
 		log.Print("ListSnapshot request failed")
 		return listSnapshotResult{}, err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "ListSnapshot")
-	if responseError != nil {
-		return listSnapshotResult{}, responseError
-	}
-
 	var result listSnapshotResult
 	if err := json.Unmarshal(response, &result); err != nil {
 		log.Print("Failed to unmarshall response from ListVolumes")
@@ -85,22 +86,47 @@ func (c *Client) getSnapshotByID(snapshot listSnapshotRequest) (listSnapshotResu
 	return result, nil
 }
 
-func (c *Client) createSnapshot(request *createSnapshotRequest) (createSnapshotResult, error) {
+// getSnapshots returns the snapshots of a volume, without the ones being deleted
+func (c *Client) getSnapshots(ctx context.Context, region string, volumeID string) ([]listSnapshotResult, error) {
+
+	baseURL := fmt.Sprintf("%s/Volumes/%s/Snapshots", region, volumeID)
+
+	_, response, err := c.CallAPIMethod(ctx, "GET", baseURL, nil)
+	if err != nil {
+		log.Print("ListSnapshots request failed")
+		return nil, err
+	}
+
+	var snapshots []listSnapshotResult
+	if err := json.Unmarshal(response, &snapshots); err != nil {
+		log.Print("Failed to unmarshall response from ListSnapshots")
+		return nil, err
+	}
+	result := make([]listSnapshotResult, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		if snapshot.LifeCycleState != "deleted" && snapshot.LifeCycleState != "deleting" {
+			result = append(result, snapshot)
+		}
+	}
+
+	return result, nil
+}
+
+func (c *Client) createSnapshot(ctx context.Context, request *createSnapshotRequest) (createSnapshotResult, error) {
 
 	params := structs.Map(request)
 
 	baseURL := fmt.Sprintf("%s/Volumes/%s/Snapshots", request.Region, request.VolumeID)
-	log.Printf("Parameters: %v", params)
+	log.Printf("Parameters: %v", redactParams(params))
 
-	statusCode, response, err := c.CallAPIMethod("POST", baseURL, params)
+	_, response, err := c.CallAPIMethod(ctx, "POST", baseURL, params)
 	if err != nil {
 		log.Print("CreateSnapshot request failed")
 		return createSnapshotResult{}, err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "CreateSnapshot")
-	if responseError != nil {
-		return createSnapshotResult{}, responseError
+	if err := c.waitForResponseJobs(ctx, request.Region, response); err != nil {
+		return createSnapshotResult{}, err
 	}
 
 	var result createSnapshotResult
@@ -112,38 +138,27 @@ func (c *Client) createSnapshot(request *createSnapshotRequest) (createSnapshotR
 	return result, nil
 }
 
-func (c *Client) deleteSnapshot(request deleteSnapshotRequest) error {
+func (c *Client) deleteSnapshot(ctx context.Context, request deleteSnapshotRequest) error {
 
 	baseURL := fmt.Sprintf("%s/Volumes/%s/Snapshots/%s", request.Region, request.VolumeID, request.SnapshotID)
-	statusCode, response, err := c.CallAPIMethod("DELETE", baseURL, nil)
+	_, response, err := c.CallAPIMethod(ctx, "DELETE", baseURL, nil)
 	if err != nil {
 		log.Print("DeleteSnapshot request failed")
 		return err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "DeleteSnapshot")
-	if responseError != nil {
-		return responseError
-	}
-
-	return nil
+	return c.waitForResponseJobs(ctx, request.Region, response)
 }
 
-func (c *Client) updateSnapshot(request updateSnapshotRequest) error {
+func (c *Client) updateSnapshot(ctx context.Context, request updateSnapshotRequest) error {
 
 	params := structs.Map(request)
 	baseURL := fmt.Sprintf("%s/Volumes/%s/Snapshots/%s", request.Region, request.VolumeID, request.SnapshotID)
-	statusCode, response, err := c.CallAPIMethod("PUT", baseURL, params)
+	_, response, err := c.CallAPIMethod(ctx, "PUT", baseURL, params)
 	if err != nil {
 		log.Print("UpdateSnapshot request failed")
 		return err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "UpdateSnapshot")
-	if responseError != nil {
-		return responseError
-	}
-
-	return nil
-
+	return c.waitForResponseJobs(ctx, request.Region, response)
 }
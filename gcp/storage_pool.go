@@ -1,6 +1,7 @@
 package gcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -28,7 +29,7 @@ type storagePool struct {
 	SharedVpcProjectNumber string
 }
 
-func (c *Client) createStoragePool(request *storagePool) (storagePool, error) {
+func (c *Client) createStoragePool(ctx context.Context, request *storagePool) (storagePool, error) {
 	var projectID string
 	if request.SharedVpcProjectNumber != "" {
 		projectID = request.SharedVpcProjectNumber
@@ -38,50 +39,31 @@ func (c *Client) createStoragePool(request *storagePool) (storagePool, error) {
 	request.Network = fmt.Sprintf("projects/%s/global/networks/%s", projectID, request.Network)
 	params := structs.Map(request)
 	baseURL := fmt.Sprintf("%s/Pools", request.Region)
-	statusCode, response, err := c.CallAPIMethod("POST", baseURL, params)
+	_, response, err := c.CallAPIMethod(ctx, "POST", baseURL, params)
 	if err != nil {
 		log.Printf("createStoragePool request failed: %#v", err)
 		return storagePool{}, err
 	}
-	responseError := apiResponseChecker(statusCode, response, "createStoragePool")
-	if responseError != nil {
-		return storagePool{}, responseError
-	}
-	var contentHolder map[string]interface{}
-	if err := json.Unmarshal(response, &contentHolder); err != nil {
-		log.Printf("Failed to unmarshall response from createStoragePool: %#v", err)
-		return storagePool{}, err
-	}
-	responseHolder := contentHolder["response"].(map[string]interface{})
-	anyValueHolder := responseHolder["AnyValue"].(map[string]interface{})
-	poolData, err := json.Marshal(anyValueHolder)
+	result, err := decodeStoragePoolOperation(response)
 	if err != nil {
-		return storagePool{}, err
-	}
-	var result storagePool
-	if err := json.Unmarshal(poolData, &result); err != nil {
 		log.Printf("Failed to unmarshall response from createStoragePool: %#v", err)
 		return storagePool{}, err
 	}
-	err = c.waitForJobCompletion(result.Region, result.Jobs[0].JobID, 1200, 20, false)
+	err = c.waitForJobs(ctx, result.Region, result.Jobs)
 	if err != nil {
 		return storagePool{}, err
 	}
 	return result, nil
 }
 
-func (c *Client) getStoragePools(location string) ([]storagePool, error) {
+func (c *Client) getStoragePools(ctx context.Context, location string) ([]storagePool, error) {
 	baseURL := fmt.Sprintf("%s/Pools", location)
 	var result []storagePool
-	statusCode, response, err := c.CallAPIMethod("GET", baseURL, nil)
+	_, response, err := c.CallAPIMethod(ctx, "GET", baseURL, nil)
 	if err != nil {
 		log.Printf("getStoragePools request failed: %#v", err)
 		return result, err
 	}
-	responseError := apiResponseChecker(statusCode, response, "getStoragePools")
-	if responseError != nil {
-		return result, responseError
-	}
 	if err := json.Unmarshal(response, &result); err != nil {
 		log.Printf("Failed to unmarshall response from getStoragePools: %#v", err)
 		return result, err
@@ -90,10 +72,10 @@ func (c *Client) getStoragePools(location string) ([]storagePool, error) {
 }
 
 // Filter all pools of the project by applying a filter function
-func (c *Client) filterAllPools(f func(storagePool) bool) ([]storagePool, error) {
+func (c *Client) filterAllPools(ctx context.Context, f func(storagePool) bool) ([]storagePool, error) {
 	filteredPools := make([]storagePool, 0)
 
-	vols, err := c.getStoragePools("-")
+	vols, err := c.getStoragePools(ctx, "-")
 	if err != nil {
 		return filteredPools, err
 	}
@@ -106,7 +88,37 @@ func (c *Client) filterAllPools(f func(storagePool) bool) ([]storagePool, error)
 	return filteredPools, nil
 }
 
-func (c *Client) getStoragePoolByID(request *storagePool) (storagePool, error) {
+// poolAllocation is the capacity of a storage pool allocated to volumes
+type poolAllocation struct {
+	AllocatedBytes int
+	VolumeCount    int
+}
+
+// getPoolAllocations sums the size of the volumes in location, by pool ID.
+// Volumes which are deleted, being deleted or in error state are not counted.
+func (c *Client) getPoolAllocations(ctx context.Context, location string) (map[string]poolAllocation, error) {
+	volumes, err := c.getVolumes(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	allocations := make(map[string]poolAllocation)
+	for _, v := range volumes {
+		if v.PoolID == "" {
+			continue
+		}
+		switch v.LifeCycleState {
+		case "deleting", "deleted", "error":
+			continue
+		}
+		allocation := allocations[v.PoolID]
+		allocation.AllocatedBytes += v.Size
+		allocation.VolumeCount++
+		allocations[v.PoolID] = allocation
+	}
+	return allocations, nil
+}
+
+func (c *Client) getStoragePoolByID(ctx context.Context, request *storagePool) (storagePool, error) {
 	var originalID string = ""
 	// terraform import will specify poolID.
 	// ID = <poolID>:<region>
@@ -120,7 +132,7 @@ func (c *Client) getStoragePoolByID(request *storagePool) (storagePool, error) {
 	if request.Region == "" {
 		// terraform import: ID = <poolID> and no region specified
 		// find all pools which match poolID
-		pools, err := c.filterAllPools(func(v storagePool) bool {
+		pools, err := c.filterAllPools(ctx, func(v storagePool) bool {
 			return v.PoolID == request.PoolID
 		})
 		if err != nil {
@@ -145,15 +157,11 @@ func (c *Client) getStoragePoolByID(request *storagePool) (storagePool, error) {
 	params := structs.Map(request)
 	baseURL := fmt.Sprintf("%s/Pools/%s", request.Region, request.PoolID)
 	var result storagePool
-	statusCode, response, err := c.CallAPIMethod("GET", baseURL, params)
+	_, response, err := c.CallAPIMethod(ctx, "GET", baseURL, params)
 	if err != nil {
 		log.Printf("getStoragePoolByID request failed: %#v", err)
 		return result, err
 	}
-	responseError := apiResponseChecker(statusCode, response, "getStoragePoolByID")
-	if responseError != nil {
-		return result, responseError
-	}
 	if err := json.Unmarshal(response, &result); err != nil {
 		log.Printf("Failed to unmarshall response from getStoragePoolByID: %#v", err)
 		return result, err
@@ -176,54 +184,51 @@ func volumeParseID(id string) (string, string, error) {
 	return parts[0], parts[1], nil
 }
 
-func (c *Client) deleteStoragePool(request *storagePool) error {
+func (c *Client) deleteStoragePool(ctx context.Context, request *storagePool) error {
 	params := structs.Map(request)
 	baseURL := fmt.Sprintf("%s/Pools/%s", request.Region, request.PoolID)
-	statusCode, response, err := c.CallAPIMethod("DELETE", baseURL, params)
+	_, response, err := c.CallAPIMethod(ctx, "DELETE", baseURL, params)
 	if err != nil {
 		log.Printf("deleteStoragePool request failed: %#v", err)
 		return err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "deleteStoragePool")
-	if responseError != nil {
-		return responseError
-	}
-	return nil
+	return c.waitForResponseJobs(ctx, request.Region, response)
 }
 
-func (c *Client) updateStoragePool(request *storagePool) error {
+func (c *Client) updateStoragePool(ctx context.Context, request *storagePool) error {
 	params := structs.Map(request)
 	baseURL := fmt.Sprintf("%s/Pools/%s", request.Region, request.PoolID)
-	statusCode, response, err := c.CallAPIMethod("PUT", baseURL, params)
+	_, response, err := c.CallAPIMethod(ctx, "PUT", baseURL, params)
 	if err != nil {
 		log.Printf("updateStoragePool request failed: %#v", err)
 		return err
 	}
-	responseError := apiResponseChecker(statusCode, response, "updateStoragePool")
-	if responseError != nil {
-		return responseError
-	}
-	var contentHolder map[string]interface{}
-	if err := json.Unmarshal(response, &contentHolder); err != nil {
-		log.Printf("Failed to unmarshall response from updateStoragePool: %#v", err)
-		return err
-	}
-	responseHolder := contentHolder["response"].(map[string]interface{})
-	anyValueHholder := responseHolder["AnyValue"].(map[string]interface{})
-	poolData, err := json.Marshal(anyValueHholder)
+	result, err := decodeStoragePoolOperation(response)
 	if err != nil {
-		panic(err)
-	}
-	var result storagePool
-	if err := json.Unmarshal(poolData, &result); err != nil {
 		log.Printf("Failed to unmarshall response from updateStoragePool: %#v", err)
 		return err
 	}
-	err = c.waitForJobCompletion(result.Region, result.Jobs[0].JobID, 1200, 20, false)
+	err = c.waitForJobs(ctx, result.Region, result.Jobs)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// decodeStoragePoolOperation returns the storage pool in response.AnyValue of an operation returned by a pool create or update
+func decodeStoragePoolOperation(response []byte) (storagePool, error) {
+	var operation struct {
+		Response struct {
+			AnyValue *storagePool `json:"AnyValue"`
+		} `json:"response"`
+	}
+	if err := json.Unmarshal(response, &operation); err != nil {
+		return storagePool{}, err
+	}
+	if operation.Response.AnyValue == nil {
+		return storagePool{}, fmt.Errorf("unexpected response without a storage pool: %s", response)
+	}
+	return *operation.Response.AnyValue, nil
+}
@@ -2,18 +2,19 @@ package gcp
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"reflect"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/fatih/structs"
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-const contextDeadlineExceededErrorMessage = "Post http://cloud-volumes-service.sde.svc.cluster.local/v2/Volumes: context deadline exceeded"
-
 // volumeRequest the users input for creating,requesting,updateing a Volume
 // exportPolicy can't set to omitempty because it could be deleted during update.
 type volumeRequest struct {
@@ -38,6 +39,8 @@ type volumeRequest struct {
 	SmbShareSettings       []string       `structs:"smbShareSettings,omitempty"`
 	BillingLabels          []billingLabel `structs:"billingLabels"`
 	SnapshotID             string         `structs:"snapshotId"`
+	BackupID               string         `structs:"backupId,omitempty"`
+	BackupPolicy           *backupPolicy  `structs:"backupPolicy,omitempty"`
 }
 
 // volumeRequest retrieves the volume attributes from API and convert to struct
@@ -65,6 +68,8 @@ type volumeResult struct {
 	UnixPermissions       string         `json:"unixPermissions,omitempty"`
 	SecurityStyle         string         `json:"securityStyle,omitempty"`
 	BillingLabels         []billingLabel `json:"billingLabels,omitempty"`
+	BackupPolicy          *backupPolicy  `json:"backupPolicy,omitempty"`
+	BackupID              string         `json:"backupId,omitempty"`
 }
 
 type billingLabel struct {
@@ -87,6 +92,7 @@ type listVolumeJobIDResult struct {
 // listVolumeIDResult the api response for listVolumeJobIDResult struct creating a volume
 type listVolumeIDResult struct {
 	VolID string `json:"volumeId"`
+	Jobs  []job  `json:"jobs"`
 }
 
 type snapshotPolicy struct {
@@ -97,6 +103,13 @@ type snapshotPolicy struct {
 	WeeklySchedule  weeklySchedule  `structs:"weeklySchedule"`
 }
 
+type backupPolicy struct {
+	Enabled              bool `structs:"enabled"`
+	DailyBackupsToKeep   int  `structs:"dailyBackupsToKeep"`
+	WeeklyBackupsToKeep  int  `structs:"weeklyBackupsToKeep"`
+	MonthlyBackupsToKeep int  `structs:"monthlyBackupsToKeep"`
+}
+
 type dailySchedule struct {
 	Hour            int `structs:"hour"`
 	Minute          int `structs:"minute"`
@@ -122,11 +135,6 @@ type weeklySchedule struct {
 	SnapshotsToKeep int    `structs:"snapshotsToKeep"`
 }
 
-type apiResponseCodeMessage struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-}
-
 type simpleExportPolicyRule struct {
 	Access              string  `structs:"access"`
 	AllowedClients      string  `structs:"allowedClients"`
@@ -155,7 +163,7 @@ type mountPoints struct {
 	ProtocolType string `structs:"protocolType"`
 }
 
-func (c *Client) getVolumeByID(volume volumeRequest) (volumeResult, error) {
+func (c *Client) getVolumeByID(ctx context.Context, volume volumeRequest) (volumeResult, error) {
 	var baseURL string
 	var originalID string = ""
 
@@ -173,7 +181,7 @@ func (c *Client) getVolumeByID(volume volumeRequest) (volumeResult, error) {
 	if volume.Region == "" {
 		// terraform import: ID = <volumeID> and no region specified
 		// find all volumes which match VolumeID
-		volumes, err := c.filterAllVolumes(func(v volumeResult) bool {
+		volumes, err := c.filterAllVolumes(ctx, func(v volumeResult) bool {
 			return v.VolumeID == volume.VolumeID
 		})
 		if err != nil {
@@ -197,14 +205,10 @@ func (c *Client) getVolumeByID(volume volumeRequest) (volumeResult, error) {
 
 	baseURL = fmt.Sprintf("%s/Volumes/%s", volume.Region, volume.VolumeID)
 
-	statusCode, response, err := c.CallAPIMethod("GET", baseURL, nil)
+	_, response, err := c.CallAPIMethod(ctx, "GET", baseURL, nil)
 	if err != nil {
 		return volumeResult{}, err
 	}
-	responseError := apiResponseChecker(statusCode, response, "getVolumeByID")
-	if responseError != nil {
-		return volumeResult{}, responseError
-	}
 
 	log.Printf("get get get: %#v", bytes.NewBuffer(response).String())
 	var result volumeResult
@@ -222,26 +226,21 @@ func (c *Client) getVolumeByID(volume volumeRequest) (volumeResult, error) {
 
 // refactored, but commented, since no code is using it currently
 // func (c *Client) getVolumeByRegion(region string) ([]volumeResult, error) {
-// 	return c.getVolumes(region)
+// 	return c.getVolumes(ctx, region)
 // }
 
 // Returns volumes of the project. region = "-" for all regions
-func (c *Client) getVolumes(region string) ([]volumeResult, error) {
+func (c *Client) getVolumes(ctx context.Context, region string) ([]volumeResult, error) {
 
 	baseURL := fmt.Sprintf("%s/Volumes", region)
 	var result []volumeResult
 
-	statusCode, response, err := c.CallAPIMethod("GET", baseURL, nil)
+	_, response, err := c.CallAPIMethod(ctx, "GET", baseURL, nil)
 	if err != nil {
 		log.Print("getVolumes request failed")
 		return result, err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "getVolumes")
-	if responseError != nil {
-		return result, responseError
-	}
-
 	if err := json.Unmarshal(response, &result); err != nil {
 		log.Print("Failed to unmarshall response from getVolumes")
 		return result, err
@@ -251,10 +250,10 @@ func (c *Client) getVolumes(region string) ([]volumeResult, error) {
 
 // Filter all volumes of the project by applying a filter function
 // Example filter function: func(v volumeResult) bool { return v.VolumeID == "1234-5678-90" }
-func (c *Client) filterAllVolumes(f func(volumeResult) bool) ([]volumeResult, error) {
+func (c *Client) filterAllVolumes(ctx context.Context, f func(volumeResult) bool) ([]volumeResult, error) {
 	filteredVolumes := make([]volumeResult, 0)
 
-	vols, err := c.getVolumes("-")
+	vols, err := c.getVolumes(ctx, "-")
 	if err != nil {
 		return filteredVolumes, err
 	}
@@ -267,7 +266,7 @@ func (c *Client) filterAllVolumes(f func(volumeResult) bool) ([]volumeResult, er
 	return filteredVolumes, nil
 }
 
-func (c *Client) getVolumeByNameOrCreationToken(volume volumeRequest) (volumeResult, error) {
+func (c *Client) getVolumeByNameOrCreationToken(ctx context.Context, volume volumeRequest) (volumeResult, error) {
 
 	if volume.Name == "" && volume.CreationToken == "" {
 		return volumeResult{}, fmt.Errorf("Either CreationToken or volume name or both are required")
@@ -275,17 +274,12 @@ func (c *Client) getVolumeByNameOrCreationToken(volume volumeRequest) (volumeRes
 
 	baseURL := fmt.Sprintf("%s/Volumes", volume.Region)
 
-	statusCode, response, err := c.CallAPIMethod("GET", baseURL, nil)
+	_, response, err := c.CallAPIMethod(ctx, "GET", baseURL, nil)
 	if err != nil {
 		log.Print("ListVolumesByName request failed")
 		return volumeResult{}, err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "getVolumeByNameOrCreationToken")
-	if responseError != nil {
-		return volumeResult{}, responseError
-	}
-
 	var result []volumeResult
 	if err := json.Unmarshal(response, &result); err != nil {
 		log.Print("Failed to unmarshall response from getVolumeByNameOrCreationToken")
@@ -319,10 +313,37 @@ func (c *Client) getVolumeByNameOrCreationToken(volume volumeRequest) (volumeRes
 	return resultVolume, nil
 }
 
-func (c *Client) createVolume(request *volumeRequest, volType string) (createVolumeResult, error) {
+// waitForVolumeAvailable looks up the volume by ID, or else by name or creation token, until it is available for use, polling every interval up to timeout.
+func (c *Client) waitForVolumeAvailable(ctx context.Context, volume volumeRequest, timeout time.Duration, interval time.Duration) (volumeResult, error) {
+	deadline := time.Now().Add(timeout)
+	lookup := c.getVolumeByNameOrCreationToken
+	if volume.VolumeID != "" {
+		lookup = c.getVolumeByID
+	}
+	for {
+		volresult, err := lookup(ctx, volume)
+		if err != nil {
+			log.Print("Error getting volume ID")
+			return volumeResult{}, err
+		}
+		if volresult.LifeCycleStateDetails == "Available for use" {
+			return volresult, nil
+		}
+		if time.Now().Add(interval).After(deadline) {
+			log.Printf("Volume %s is not ready.\n", volresult.Name)
+			return volumeResult{}, fmt.Errorf("volume %s is not ready after %v: %s", volresult.Name, timeout, volresult.LifeCycleStateDetails)
+		}
+		log.Printf("Volume %s is not ready. Wait for %v and check again.\n", volresult.Name, interval)
+		if err := sleepWithContext(ctx, interval); err != nil {
+			return volumeResult{}, err
+		}
+	}
+}
+
+func (c *Client) createVolume(ctx context.Context, request *volumeRequest, volType string) (createVolumeResult, error) {
 
 	if request.CreationToken == "" {
-		creationToken, err := c.createVolumeCreationToken(*request)
+		creationToken, err := c.createVolumeCreationToken(ctx, *request)
 		if err != nil {
 			log.Print("CreateVolume request failed")
 			return createVolumeResult{}, err
@@ -340,81 +361,10 @@ func (c *Client) createVolume(request *volumeRequest, volType string) (createVol
 
 	params := structs.Map(request)
 	baseURL := fmt.Sprintf("%s/%s", request.Region, volType)
-	statusCode, response, err := c.CallAPIMethod("POST", baseURL, params)
+	_, response, err := c.CallAPIMethod(ctx, "POST", baseURL, params)
 	if err != nil {
 		return createVolumeResult{}, err
 	}
-	responseError := apiResponseChecker(statusCode, response, "createVolume")
-	if responseError != nil {
-		var responseErrorContent apiErrorResponse
-		responseContent := bytes.NewBuffer(response).String()
-		if err := json.Unmarshal(response, &responseErrorContent); err != nil {
-			return createVolumeResult{}, fmt.Errorf(responseContent)
-		}
-		if responseErrorContent.Code >= 300 || responseErrorContent.Code < 200 {
-			spawnJobCreationErrorMessage := fmt.Sprintf("Error creating volume - Cannot spawn additional jobs in %s for this network . Please wait for the ongoing jobs to finish in zone %s and try again", request.Zone, request.Zone)
-			log.Printf("* Response error message on createVolume: %v", responseErrorContent.Message)
-			if responseErrorContent.Message == spawnJobCreationErrorMessage {
-				retries := 10
-				for retries > 0 {
-					log.Printf("* Retries %v", retries)
-					var spawnJobResponseErrorContent apiErrorResponse
-					time.Sleep(time.Duration(nextRandomInt(30, 50)) * time.Second)
-					statusCode, response, err = c.CallAPIMethod("POST", baseURL, params)
-					if err != nil {
-						return createVolumeResult{}, err
-					}
-					responseError = apiResponseChecker(statusCode, response, "createVolume")
-					responseContent = bytes.NewBuffer(response).String()
-					if err := json.Unmarshal(response, &spawnJobResponseErrorContent); err != nil {
-						return createVolumeResult{}, fmt.Errorf(responseContent)
-					}
-					if spawnJobResponseErrorContent.Code == 0 {
-						var result createVolumeResult
-						if err := json.Unmarshal(response, &result); err != nil {
-							log.Print("Failed to unmarshall response from createVolume")
-							return createVolumeResult{}, fmt.Errorf(bytes.NewBuffer(response).String())
-						}
-						return result, nil
-					}
-					if spawnJobResponseErrorContent.Message != spawnJobCreationErrorMessage {
-						log.Printf("Retry failed spawnJobResponseErrorContent: %v", spawnJobResponseErrorContent.Message)
-						return createVolumeResult{}, responseError
-					}
-					retries--
-				}
-			} else if responseErrorContent.Message == contextDeadlineExceededErrorMessage {
-				retries := 5
-				for retries > 0 {
-					var contextDeadlineResponseErrorContent apiErrorResponse
-					time.Sleep(time.Duration(nextRandomInt(5, 10)) * time.Second)
-					statusCode, response, err = c.CallAPIMethod("POST", baseURL, params)
-					if err != nil {
-						return createVolumeResult{}, err
-					}
-					responseError = apiResponseChecker(statusCode, response, "createVolume")
-					responseContent = bytes.NewBuffer(response).String()
-					if err := json.Unmarshal(response, &contextDeadlineResponseErrorContent); err != nil {
-						return createVolumeResult{}, fmt.Errorf(responseContent)
-					}
-					if contextDeadlineResponseErrorContent.Code == 0 {
-						var result createVolumeResult
-						if err := json.Unmarshal(response, &result); err != nil {
-							log.Print("Failed to unmarshall response from createVolume")
-							return createVolumeResult{}, fmt.Errorf(bytes.NewBuffer(response).String())
-						}
-						return result, nil
-					}
-					if contextDeadlineResponseErrorContent.Message != contextDeadlineExceededErrorMessage {
-						return createVolumeResult{}, responseError
-					}
-					retries--
-				}
-			} else {
-				return createVolumeResult{}, responseError
-			}
-		}
-	}
 
 	var result createVolumeResult
 	if err := json.Unmarshal(response, &result); err != nil {
@@ -425,59 +375,17 @@ func (c *Client) createVolume(request *volumeRequest, volType string) (createVol
 	return result, nil
 }
 
-func (c *Client) deleteVolume(request volumeRequest) error {
+func (c *Client) deleteVolume(ctx context.Context, request volumeRequest) error {
 	log.Print("deleteVolume...")
 	baseURL := fmt.Sprintf("%s/Volumes/%s", request.Region, request.VolumeID)
-	statusCode, response, err := c.CallAPIMethod("DELETE", baseURL, nil)
+	_, response, err := c.CallAPIMethod(ctx, "DELETE", baseURL, nil)
 	if err != nil {
 		log.Print("DeleteVolume request failed")
 		return err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "deleteVolume")
-	if responseError != nil {
-		var responseErrorContent apiErrorResponse
-		responseContent := bytes.NewBuffer(response).String()
-		if err := json.Unmarshal(response, &responseErrorContent); err != nil {
-			return fmt.Errorf(responseContent)
-		}
-		if responseErrorContent.Code >= 300 || responseErrorContent.Code < 200 {
-			spawnJobDeletionErrorMessage := fmt.Sprintf("Error deleting volume - Cannot spawn additional jobs in %s for this network . Please wait for the ongoing jobs to finish in zone %s and try again", request.Zone, request.Zone)
-			log.Printf("* Response error message on deleteVolume: %v", responseErrorContent.Message)
-			if responseErrorContent.Message == spawnJobDeletionErrorMessage {
-				retries := 10
-				for retries > 0 {
-					log.Printf("retries %v", retries)
-					var deleteJobResponseErrorContent apiErrorResponse
-					time.Sleep(time.Duration(nextRandomInt(30, 50)) * time.Second)
-					statusCode, response, err = c.CallAPIMethod("DELETE", baseURL, nil)
-					if err != nil {
-						return err
-					}
-					responseError = apiResponseChecker(statusCode, response, "deleteVolume")
-					responseContent = bytes.NewBuffer(response).String()
-					if err := json.Unmarshal(response, &deleteJobResponseErrorContent); err != nil {
-						return fmt.Errorf(responseContent)
-					}
-					if deleteJobResponseErrorContent.Code == 0 {
-						var result createVolumeResult
-						if err := json.Unmarshal(response, &result); err != nil {
-							log.Print("Failed to unmarshall response from createVolume")
-							return fmt.Errorf(bytes.NewBuffer(response).String())
-						}
-						return nil
-					}
-					if deleteJobResponseErrorContent.Message != spawnJobDeletionErrorMessage {
-						log.Printf("Retry failed deleteJobResponseErrorContent: %v", deleteJobResponseErrorContent.Message)
-						return responseError
-					}
-					retries--
-				}
-
-			} else {
-				return responseError
-			}
-		}
+	if err := c.waitForResponseJobs(ctx, request.Region, response); err != nil {
+		return err
 	}
 
 	var result apiErrorResponse
@@ -489,22 +397,17 @@ func (c *Client) deleteVolume(request volumeRequest) error {
 	return nil
 }
 
-func (c *Client) createVolumeCreationToken(request volumeRequest) (volumeResult, error) {
+func (c *Client) createVolumeCreationToken(ctx context.Context, request volumeRequest) (volumeResult, error) {
 	params := structs.Map(request)
 
 	baseURL := fmt.Sprintf("%s/VolumeCreationToken", request.Region)
 	log.Printf("Parameters: %v", params)
-	statusCode, response, err := c.CallAPIMethod("GET", baseURL, params)
+	_, response, err := c.CallAPIMethod(ctx, "GET", baseURL, params)
 	if err != nil {
 		log.Print("CreationToken request failed")
 		return volumeResult{}, err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "createVolumeCreationToken")
-	if responseError != nil {
-		return volumeResult{}, responseError
-	}
-
 	var result volumeResult
 	if err := json.Unmarshal(response, &result); err != nil {
 		log.Print("Failed to unmarshall response from createVolumeCreationToken")
@@ -513,32 +416,41 @@ func (c *Client) createVolumeCreationToken(request volumeRequest) (volumeResult,
 	return result, nil
 }
 
-func (c *Client) updateVolume(request volumeRequest) error {
+func (c *Client) updateVolume(ctx context.Context, request volumeRequest) error {
 	params := structs.Map(request)
 
 	baseURL := fmt.Sprintf("%s/Volumes/%s", request.Region, request.VolumeID)
 
-	statusCode, response, err := c.CallAPIMethod("PUT", baseURL, params)
+	_, response, err := c.CallAPIMethod(ctx, "PUT", baseURL, params)
 	if err != nil {
 		log.Print("updateVolume request failed")
 		return err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "updateVolume")
-	if responseError != nil {
-		return responseError
-	}
+	// error responses are returned as a *restapi.ResponseError by CallAPIMethod
+	return c.waitForResponseJobs(ctx, request.Region, response)
+}
 
-	var result apiResponseCodeMessage
-	if err := json.Unmarshal(response, &result); err != nil {
-		log.Print("Failed to unmarshall response from updateVolume")
+// revertVolumeRequest requests reverting a volume to one of its snapshots
+type revertVolumeRequest struct {
+	Region     string `structs:"region"`
+	VolumeID   string `structs:"volumeId"`
+	SnapshotID string `structs:"snapshotId"`
+}
+
+// revertVolume reverts the volume to the snapshot and waits for the revert job. Data written after the snapshot is lost.
+func (c *Client) revertVolume(ctx context.Context, request revertVolumeRequest) error {
+	params := structs.Map(request)
+
+	baseURL := fmt.Sprintf("%s/Volumes/%s/Revert", request.Region, request.VolumeID)
+
+	_, response, err := c.CallAPIMethod(ctx, "POST", baseURL, params)
+	if err != nil {
+		log.Print("revertVolume request failed")
 		return err
 	}
-	if (result.Code != 0 && result.Code != 200) || (result.Message != "") {
-		return fmt.Errorf("code: %d, message: %s", result.Code, result.Message)
-	}
 
-	return nil
+	return c.waitForResponseJobs(ctx, request.Region, response)
 }
 
 // SetProjectID for the client to use for requests to the GCP API
@@ -621,6 +533,25 @@ func expandSnapshotPolicy(data map[string]interface{}) snapshotPolicy {
 	return snapshotPolicy
 }
 
+// expandBackupPolicy converts map to backupPolicy struct
+func expandBackupPolicy(data map[string]interface{}) *backupPolicy {
+	backupPolicy := backupPolicy{}
+
+	if v, ok := data["enabled"]; ok {
+		backupPolicy.Enabled = v.(bool)
+	}
+	if v, ok := data["daily_backups_to_keep"]; ok {
+		backupPolicy.DailyBackupsToKeep = v.(int)
+	}
+	if v, ok := data["weekly_backups_to_keep"]; ok {
+		backupPolicy.WeeklyBackupsToKeep = v.(int)
+	}
+	if v, ok := data["monthly_backups_to_keep"]; ok {
+		backupPolicy.MonthlyBackupsToKeep = v.(int)
+	}
+	return &backupPolicy
+}
+
 // flattenExportPolicy converts exportPolicy struct to []map[string]interface{}
 func flattenExportPolicy(v exportPolicy) interface{} {
 	exportPolicyRules := v.Rules
@@ -732,6 +663,19 @@ func flattenSnapshotPolicy(v snapshotPolicy) interface{} {
 	return flattened
 }
 
+// flattenBackupPolicy converts backupPolicy struct to []map[string]interface{}
+func flattenBackupPolicy(v *backupPolicy) interface{} {
+	if v == nil {
+		return []map[string]interface{}{}
+	}
+	return []map[string]interface{}{{
+		"enabled":                 v.Enabled,
+		"daily_backups_to_keep":   v.DailyBackupsToKeep,
+		"weekly_backups_to_keep":  v.WeeklyBackupsToKeep,
+		"monthly_backups_to_keep": v.MonthlyBackupsToKeep,
+	}}
+}
+
 func flattenMountPoints(v []mountPoints) interface{} {
 	mps := make([]map[string]interface{}, 0, len(v))
 	for _, mountpoint := range v {
@@ -755,14 +699,72 @@ func flattenBillingLabel(v []billingLabel) interface{} {
 	return labels
 }
 
-func expandBillingLabel(set *schema.Set) []billingLabel {
+// expandBillingLabel merges the default billing labels of the provider with the billing labels of the resource,
+// which take precedence for the same key
+func expandBillingLabel(set *schema.Set, defaults map[string]string) []billingLabel {
 	var billingLabels []billingLabel
+	keys := make(map[string]bool)
 	for _, v := range set.List() {
 		billingLabel := billingLabel{}
 		blabel := v.(map[string]interface{})
 		billingLabel.Key = blabel["key"].(string)
 		billingLabel.Value = blabel["value"].(string)
 		billingLabels = append(billingLabels, billingLabel)
+		keys[billingLabel.Key] = true
+	}
+	defaultKeys := make([]string, 0, len(defaults))
+	for k := range defaults {
+		if !keys[k] {
+			defaultKeys = append(defaultKeys, k)
+		}
+	}
+	sort.Strings(defaultKeys)
+	for _, k := range defaultKeys {
+		billingLabels = append(billingLabels, billingLabel{Key: k, Value: defaults[k]})
 	}
 	return billingLabels
 }
+
+// flattenEffectiveBillingLabels converts all billing labels of a volume or storage pool, including the default
+// billing labels of the provider, to a map
+func flattenEffectiveBillingLabels(labels []billingLabel) map[string]interface{} {
+	result := make(map[string]interface{}, len(labels))
+	for _, l := range labels {
+		result[l.Key] = l.Value
+	}
+	return result
+}
+
+// customizeDiffEffectiveBillingLabels plans an update of an existing volume or storage pool when its billing labels
+// differ from billing_label merged with the default billing labels, like after default_billing_labels changed
+func customizeDiffEffectiveBillingLabels(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() == "" {
+		return nil
+	}
+	if !diff.NewValueKnown("billing_label") {
+		return diff.SetNewComputed("effective_billing_labels")
+	}
+	client := meta.(*Client)
+	expected := flattenEffectiveBillingLabels(expandBillingLabel(diff.Get("billing_label").(*schema.Set), client.DefaultBillingLabels))
+	if reflect.DeepEqual(diff.Get("effective_billing_labels"), expected) {
+		return nil
+	}
+	return diff.SetNew("effective_billing_labels", expected)
+}
+
+// removeDefaultBillingLabels drops the default billing labels of the provider from the labels read from the API,
+// unless the resource sets the same key, so provider-injected labels don't show up as a diff
+func removeDefaultBillingLabels(labels []billingLabel, defaults map[string]string, set *schema.Set) []billingLabel {
+	keys := make(map[string]bool)
+	for _, v := range set.List() {
+		keys[v.(map[string]interface{})["key"].(string)] = true
+	}
+	result := make([]billingLabel, 0, len(labels))
+	for _, l := range labels {
+		if value, ok := defaults[l.Key]; ok && value == l.Value && !keys[l.Key] {
+			continue
+		}
+		result = append(result, l)
+	}
+	return result
+}
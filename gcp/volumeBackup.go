@@ -1,9 +1,11 @@
 package gcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/fatih/structs"
 )
@@ -39,8 +41,24 @@ type deleteVolumeBackupRequest struct {
 
 // listVolumeBackupResult lists the volume for given VolumeBackup ID
 type listVolumeBackupResult struct {
-	VolumeBackupID string `json:"backupId"`
-	LifeCycleState string `json:"lifeCycleState"`
+	VolumeBackupID        string `json:"backupId"`
+	Name                  string `json:"name"`
+	VolumeID              string `json:"volumeId"`
+	Created               string `json:"created"`
+	Completed             string `json:"completed"`
+	BytesTransferred      int64  `json:"bytesTransferred"`
+	BackupType            string `json:"backupType"`
+	LifeCycleState        string `json:"lifeCycleState"`
+	LifeCycleStateDetails string `json:"lifeCycleStateDetails"`
+	BackupRegion          string `json:"backupRegion"`
+}
+
+// storedIn returns the region the backup is stored in. Without a backup vault this is the region of the volume.
+func (b listVolumeBackupResult) storedIn(volumeRegion string) string {
+	if b.BackupRegion != "" {
+		return b.BackupRegion
+	}
+	return volumeRegion
 }
 
 // listVolumeBackupRequest requests the volume for given VolumeBackup ID and region
@@ -58,21 +76,16 @@ type updateVolumeBackupRequest struct {
 	VolumeBackupID string `structs:"backupId"`
 }
 
-func (c *Client) getVolumeBackupByID(VolumeBackup listVolumeBackupRequest) (listVolumeBackupResult, error) {
+func (c *Client) getVolumeBackupByID(ctx context.Context, VolumeBackup listVolumeBackupRequest) (listVolumeBackupResult, error) {
 
 	baseURL := fmt.Sprintf("%s/Volumes/%s/Backups/%s", VolumeBackup.Region, VolumeBackup.VolumeID, VolumeBackup.VolumeBackupID)
 
-	statusCode, response, err := c.CallAPIMethod("GET", baseURL, nil)
+	_, response, err := c.CallAPIMethod(ctx, "GET", baseURL, nil)
 	if err != nil {
 		log.Print("ListVolumeBackup request failed")
 		return listVolumeBackupResult{}, err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "ListVolumeBackup")
-	if responseError != nil {
-		return listVolumeBackupResult{}, responseError
-	}
-
 	var result listVolumeBackupResult
 	if err := json.Unmarshal(response, &result); err != nil {
 		log.Print("Failed to unmarshall response from ListVolumes")
@@ -85,22 +98,73 @@ func (c *Client) getVolumeBackupByID(VolumeBackup listVolumeBackupRequest) (list
 	return result, nil
 }
 
-func (c *Client) createVolumeBackup(request *createVolumeBackupRequest) (createVolumeBackupResult, error) {
+// getVolumeBackups returns the backups of a volume, without the ones being deleted
+func (c *Client) getVolumeBackups(ctx context.Context, region string, volumeID string) ([]listVolumeBackupResult, error) {
+
+	baseURL := fmt.Sprintf("%s/Volumes/%s/Backups", region, volumeID)
+
+	_, response, err := c.CallAPIMethod(ctx, "GET", baseURL, nil)
+	if err != nil {
+		log.Print("ListVolumeBackups request failed")
+		return nil, err
+	}
+
+	var backups []listVolumeBackupResult
+	if err := json.Unmarshal(response, &backups); err != nil {
+		log.Print("Failed to unmarshall response from ListVolumeBackups")
+		return nil, err
+	}
+	result := make([]listVolumeBackupResult, 0, len(backups))
+	for _, backup := range backups {
+		if backup.LifeCycleState != "deleted" && backup.LifeCycleState != "deleting" {
+			result = append(result, backup)
+		}
+	}
+
+	return result, nil
+}
+
+// getRegionVolumeBackupByID looks up a backup by ID among the backups of all volumes in the region and the backups
+// stored in the region by a backup vault of another region. This also finds backups of volumes which were deleted.
+func (c *Client) getRegionVolumeBackupByID(ctx context.Context, region string, backupID string) (listVolumeBackupResult, error) {
+
+	baseURL := fmt.Sprintf("%s/Backups", region)
+
+	_, response, err := c.CallAPIMethod(ctx, "GET", baseURL, nil)
+	if err != nil {
+		log.Print("ListRegionVolumeBackups request failed")
+		return listVolumeBackupResult{}, err
+	}
+
+	var backups []listVolumeBackupResult
+	if err := json.Unmarshal(response, &backups); err != nil {
+		log.Print("Failed to unmarshall response from ListRegionVolumeBackups")
+		return listVolumeBackupResult{}, err
+	}
+	for _, backup := range backups {
+		if backup.VolumeBackupID == backupID {
+			return backup, nil
+		}
+	}
+
+	return listVolumeBackupResult{}, fmt.Errorf("No backup found with ID %s in region %s", backupID, region)
+}
+
+func (c *Client) createVolumeBackup(ctx context.Context, request *createVolumeBackupRequest) (createVolumeBackupResult, error) {
 
 	params := structs.Map(request)
 
 	baseURL := fmt.Sprintf("%s/Volumes/%s/Backups", request.Region, request.VolumeID)
-	log.Printf("Parameters: %v", params)
+	log.Printf("Parameters: %v", redactParams(params))
 
-	statusCode, response, err := c.CallAPIMethod("POST", baseURL, params)
+	_, response, err := c.CallAPIMethod(ctx, "POST", baseURL, params)
 	if err != nil {
 		log.Print("CreateVolumeBackup request failed")
 		return createVolumeBackupResult{}, err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "CreateVolumeBackup")
-	if responseError != nil {
-		return createVolumeBackupResult{}, responseError
+	if err := c.waitForResponseJobs(ctx, request.Region, response); err != nil {
+		return createVolumeBackupResult{}, err
 	}
 
 	var result createVolumeBackupResult
@@ -112,19 +176,49 @@ func (c *Client) createVolumeBackup(request *createVolumeBackupRequest) (createV
 	return result, nil
 }
 
-func (c *Client) deleteVolumeBackup(request deleteVolumeBackupRequest) error {
+func (c *Client) deleteVolumeBackup(ctx context.Context, request deleteVolumeBackupRequest) error {
 
 	baseURL := fmt.Sprintf("%s/Volumes/%s/Backups/%s", request.Region, request.VolumeID, request.VolumeBackupID)
-	statusCode, response, err := c.CallAPIMethod("DELETE", baseURL, nil)
+	_, response, err := c.CallAPIMethod(ctx, "DELETE", baseURL, nil)
 	if err != nil {
 		log.Print("DeleteVolumeBackup request failed")
 		return err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "DeleteVolumeBackup")
-	if responseError != nil {
-		return responseError
+	return c.waitForResponseJobs(ctx, request.Region, response)
+}
+
+func (c *Client) updateVolumeBackup(ctx context.Context, request updateVolumeBackupRequest) error {
+
+	params := structs.Map(request)
+	baseURL := fmt.Sprintf("%s/Volumes/%s/Backups/%s", request.Region, request.VolumeID, request.VolumeBackupID)
+	_, response, err := c.CallAPIMethod(ctx, "PUT", baseURL, params)
+	if err != nil {
+		log.Print("UpdateVolumeBackup request failed")
+		return err
 	}
 
-	return nil
+	return c.waitForResponseJobs(ctx, request.Region, response)
+}
+
+// waitForVolumeBackupAvailable polls the backup every interval until it is available. The wait is bounded by ctx.
+func (c *Client) waitForVolumeBackupAvailable(ctx context.Context, request listVolumeBackupRequest, interval time.Duration) (listVolumeBackupResult, error) {
+	for {
+		result, err := c.getVolumeBackupByID(ctx, request)
+		if err != nil {
+			return listVolumeBackupResult{}, err
+		}
+		switch result.LifeCycleState {
+		case "available":
+			return result, nil
+		case "error":
+			return listVolumeBackupResult{}, fmt.Errorf("backup %s is in error state: %s", request.VolumeBackupID, result.LifeCycleStateDetails)
+		case "":
+			return listVolumeBackupResult{}, fmt.Errorf("backup %s was deleted", request.VolumeBackupID)
+		}
+		log.Printf("Backup %s is %s. Wait for %v and check again.", request.VolumeBackupID, result.LifeCycleState, interval)
+		if err := sleepWithContext(ctx, interval); err != nil {
+			return listVolumeBackupResult{}, fmt.Errorf("timed out waiting for backup %s to be available: %s", request.VolumeBackupID, err)
+		}
+	}
 }
@@ -1,10 +1,10 @@
 package gcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
-	"time"
 
 	"github.com/fatih/structs"
 )
@@ -25,36 +25,42 @@ type volumeReplicationRequest struct {
 }
 
 type volumeReplicationResult struct {
-	Bandwidth             string `json:"bandwidth,omitempty"`
-	DestinationVolumeID   string `json:"destinationVolumeUUID,omitempty"`
-	EndpointType          string `json:"endpointType,omitempty"`
-	LifeCycleState        string `json:"lifeCycleState,omitempty"`
-	LifeCycleStateDetails string `json:"lifeCycleStateDetails,omitempty"`
-	MirrorState           string `json:"mirrorState,omitempty"`
-	Name                  string `json:"name,omitempty"`
-	Policy                string `json:"replicationPolicy,omitempty"`
-	RelationshipStatus    string `json:"relationshipStatus,omitempty"`
-	RemoteRegion          string `json:"remoteRegion,omitempty"`
-	ReplicationID         string `json:"volumeReplicationUUID,omitempty"`
-	Schedule              string `json:"replicationSchedule,omitempty"`
-	SourceVolumeID        string `json:"sourceVolumeUUID,omitempty"`
+	Bandwidth             string                         `json:"bandwidth,omitempty"`
+	DestinationVolumeID   string                         `json:"destinationVolumeUUID,omitempty"`
+	EndpointType          string                         `json:"endpointType,omitempty"`
+	LifeCycleState        string                         `json:"lifeCycleState,omitempty"`
+	LifeCycleStateDetails string                         `json:"lifeCycleStateDetails,omitempty"`
+	MirrorState           string                         `json:"mirrorState,omitempty"`
+	Name                  string                         `json:"name,omitempty"`
+	Policy                string                         `json:"replicationPolicy,omitempty"`
+	Region                string                         `json:"region,omitempty"`
+	RelationshipStatus    string                         `json:"relationshipStatus,omitempty"`
+	RemoteRegion          string                         `json:"remoteRegion,omitempty"`
+	ReplicationID         string                         `json:"volumeReplicationUUID,omitempty"`
+	Schedule              string                         `json:"replicationSchedule,omitempty"`
+	SourceVolumeID        string                         `json:"sourceVolumeUUID,omitempty"`
+	TransferStats         volumeReplicationTransferStats `json:"transferStats,omitempty"`
 }
 
-func (c *Client) getVolumeReplicationByID(replica volumeReplicationRequest) (volumeReplicationResult, error) {
+// volumeReplicationTransferStats describes the transfers of a volume replication. Durations are in seconds.
+type volumeReplicationTransferStats struct {
+	LagTime              int64  `json:"lagTime,omitempty"`
+	LastTransferDuration int64  `json:"lastTransferDuration,omitempty"`
+	LastTransferEndTime  string `json:"lastTransferEndTime,omitempty"`
+	LastTransferSize     int64  `json:"lastTransferSize,omitempty"`
+	TotalTransferBytes   int64  `json:"totalTransferBytes,omitempty"`
+}
+
+func (c *Client) getVolumeReplicationByID(ctx context.Context, replica volumeReplicationRequest) (volumeReplicationResult, error) {
 
 	baseURL := fmt.Sprintf("%s/VolumeReplications/%s", replica.Region, replica.ReplicationID)
 
-	statusCode, response, err := c.CallAPIMethod("GET", baseURL, nil)
+	_, response, err := c.CallAPIMethod(ctx, "GET", baseURL, nil)
 	if err != nil {
 		log.Print("getVolumeReplicationByID request failed")
 		return volumeReplicationResult{}, err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "getVolumeReplicationByID")
-	if responseError != nil {
-		return volumeReplicationResult{}, responseError
-	}
-
 	var result volumeReplicationResult
 	if err := json.Unmarshal(response, &result); err != nil {
 		log.Print("Failed to unmarshall response from getVolumeReplicationByID")
@@ -68,37 +74,47 @@ func (c *Client) getVolumeReplicationByID(replica volumeReplicationRequest) (vol
 	return result, nil
 }
 
-func (c *Client) createVolumeReplication(replica *volumeReplicationRequest) (volumeReplicationResult, error) {
-	baseURL := fmt.Sprintf("%s/VolumeReplications", replica.Region)
+// getVolumeReplications lists the volume replications of a region, or of all regions when region is "-".
+// Replications being deleted are skipped.
+func (c *Client) getVolumeReplications(ctx context.Context, region string) ([]volumeReplicationResult, error) {
 
-	params := structs.Map(replica)
+	baseURL := fmt.Sprintf("%s/VolumeReplications", region)
 
-	statusCode, response, err := c.CallAPIMethod("POST", baseURL, params)
+	_, response, err := c.CallAPIMethod(ctx, "GET", baseURL, nil)
 	if err != nil {
-		log.Print("createVolumeReplication request failed")
-		return volumeReplicationResult{}, err
+		log.Print("getVolumeReplications request failed")
+		return nil, err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "createVolumeReplication")
-	if responseError != nil {
-		return volumeReplicationResult{}, responseError
+	var replications []volumeReplicationResult
+	if err := json.Unmarshal(response, &replications); err != nil {
+		log.Print("Failed to unmarshall response from getVolumeReplications")
+		return nil, err
 	}
 
-	var f interface{}
-	err = json.Unmarshal(response, &f)
+	result := make([]volumeReplicationResult, 0, len(replications))
+	for _, replica := range replications {
+		if replica.LifeCycleState == "deleted" || replica.LifeCycleState == "deleting" {
+			continue
+		}
+		result = append(result, replica)
+	}
+	return result, nil
+}
+
+func (c *Client) createVolumeReplication(ctx context.Context, replica *volumeReplicationRequest) (volumeReplicationResult, error) {
+	baseURL := fmt.Sprintf("%s/VolumeReplications", replica.Region)
+
+	params := structs.Map(replica)
+
+	_, response, err := c.CallAPIMethod(ctx, "POST", baseURL, params)
 	if err != nil {
+		log.Print("createVolumeReplication request failed")
 		return volumeReplicationResult{}, err
 	}
-	m := f.(map[string]interface{})
-	jobs := m["jobs"].([]interface{})
-	for _, v := range jobs {
-		job := v.(map[string]interface{})
-		if job["action"].(string) == "create" {
-			err := c.waitForJobCompletion(replica.Region, job["jobId"].(string), 600, 10, false)
-			if err != nil {
-				return volumeReplicationResult{}, err
-			}
-		}
+
+	if err := c.waitForResponseJobs(ctx, replica.Region, response); err != nil {
+		return volumeReplicationResult{}, err
 	}
 
 	var result volumeReplicationResult
@@ -110,125 +126,46 @@ func (c *Client) createVolumeReplication(replica *volumeReplicationRequest) (vol
 	return result, nil
 }
 
-func (c *Client) breakVolumeReplication(replica *volumeReplicationRequest) error {
-	baseURL := fmt.Sprintf("%s/VolumeReplications/%s/Break", replica.Region, replica.ReplicationID)
-	statusCode, response, err := c.CallAPIMethod("POST", baseURL, nil)
-	if err != nil {
-		log.Print("breakVolumeReplication request failed")
-		return err
-	}
-	responseError := apiResponseChecker(statusCode, response, "breakVolumeReplication")
-	if responseError != nil {
-		return responseError
-	}
+func (c *Client) breakVolumeReplication(ctx context.Context, replica *volumeReplicationRequest) error {
+	return c.volumeReplicationAction(ctx, replica, "Break")
+}
 
-	var f interface{}
-	err = json.Unmarshal(response, &f)
+// volumeReplicationAction runs an action like Break, Resync, Suspend, Resume or Reverse on a volume replication
+// and waits for its jobs
+func (c *Client) volumeReplicationAction(ctx context.Context, replica *volumeReplicationRequest, action string) error {
+	baseURL := fmt.Sprintf("%s/VolumeReplications/%s/%s", replica.Region, replica.ReplicationID, action)
+	_, response, err := c.CallAPIMethod(ctx, "POST", baseURL, nil)
 	if err != nil {
+		log.Printf("%s volume replication request failed", action)
 		return err
 	}
-	m := f.(map[string]interface{})
-	content := m["response"].(map[string]interface{})
-	anyValue := content["AnyValue"].(map[string]interface{})
-	jobs := anyValue["jobs"].([]interface{})
-	for _, v := range jobs {
-		job := v.(map[string]interface{})
-		if job["action"].(string) == "break" {
-			err := c.waitForJobCompletion(replica.Region, job["jobId"].(string), 600, 10, false)
-			if err != nil {
-				return err
-			}
-		}
-	}
-	return nil
-}
 
-// Given a jobID and region, wait for the job to finish. All measurments are in seconds.
-// if waitUntilCompleted is true, it will not return until the job is done or encounters error.
-func (c *Client) waitForJobCompletion(region string, jobID string, timeout int, interval int, waitUntilCompleted bool) error {
-
-	for timeout > 0 || waitUntilCompleted {
-		if timeout > 0 {
-			timeout -= interval
-		}
-		time.Sleep(time.Duration(interval) * time.Second)
-		jobDetail, err := c.getJobByID(region, jobID)
-		if err != nil {
-			return err
-		}
-		if jobDetail.State == "done" {
-			return nil
-		}
-		if jobDetail.State == "error" {
-			return fmt.Errorf(jobDetail.StateDetails)
-		}
-	}
-	return fmt.Errorf("job timed out after %d seconds", timeout)
+	return c.waitForResponseJobs(ctx, replica.Region, response)
 }
 
-func (c *Client) deleteVolumeReplication(replica *volumeReplicationRequest) error {
+func (c *Client) deleteVolumeReplication(ctx context.Context, replica *volumeReplicationRequest) error {
 	baseURL := fmt.Sprintf("%s/VolumeReplications/%s", replica.Region, replica.ReplicationID)
 
-	statusCode, response, err := c.CallAPIMethod("DELETE", baseURL, nil)
+	_, response, err := c.CallAPIMethod(ctx, "DELETE", baseURL, nil)
 	if err != nil {
 		log.Print("deleteVolumeReplication request failed")
 		return err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "deleteVolumeReplication")
-	if responseError != nil {
-		return responseError
-	}
-
-	return nil
-
+	return c.waitForResponseJobs(ctx, replica.Region, response)
 }
 
-func (c *Client) updateVolumeReplication(replica *volumeReplicationRequest) error {
+func (c *Client) updateVolumeReplication(ctx context.Context, replica *volumeReplicationRequest) error {
 
 	baseURL := fmt.Sprintf("%s/VolumeReplications/%s", replica.Region, replica.ReplicationID)
 
 	params := structs.Map(replica)
 
-	statusCode, response, err := c.CallAPIMethod("PUT", baseURL, params)
+	_, response, err := c.CallAPIMethod(ctx, "PUT", baseURL, params)
 	if err != nil {
 		log.Print("updateVolumeReplication request failed")
 		return err
 	}
 
-	responseError := apiResponseChecker(statusCode, response, "updateVolumeReplication")
-	if responseError != nil {
-		return responseError
-	}
-
-	return nil
-}
-
-func (c *Client) getJobByID(region string, jobID string) (job, error) {
-
-	baseURL := fmt.Sprintf("%s/Jobs/%s", region, jobID)
-
-	statusCode, response, err := c.CallAPIMethod("GET", baseURL, nil)
-	if err != nil {
-		log.Print("updateVolumeReplication request failed")
-		return job{}, err
-	}
-	responseError := apiResponseChecker(statusCode, response, "getJobByID")
-	if responseError != nil {
-		return job{}, responseError
-	}
-	var result job
-	if err := json.Unmarshal(response, &result); err != nil {
-		log.Print("Failed to unmarshall response from getJobByID")
-		return job{}, err
-	}
-	log.Printf("get job id: %#v", result)
-	return result, nil
-
-}
-
-type job struct {
-	State        string `json:"state"`
-	StateDetails string `json:"stateDetails"`
-	JobID        string `json:"jobId"`
+	return c.waitForResponseJobs(ctx, replica.Region, response)
 }